@@ -0,0 +1,33 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docket
+
+import (
+	"context"
+
+	"github.com/bloomberg/docket/internal/compose"
+)
+
+// docketBuild builds the images of the services with build sections before they come up, so
+// that a broken Dockerfile is reported as a build failure instead of a failure to start the
+// services. DOCKET_BUILD_NO_CACHE, DOCKET_BUILD_PULL, DOCKET_BUILD_ARGS, and
+// DOCKET_BUILD_PARALLEL set the options.
+func docketBuild(ctx context.Context, cmp *compose.Compose) error {
+	if err := cmp.Build(ctx, compose.BuildOptionsFromEnv()); err != nil {
+		return &phaseError{phase: "build", err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,288 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bloomberg/docket/internal/tempbuild"
+)
+
+// cacheKeyInputs are the things that determine whether a cached dkt executable is still the
+// one that `go build` would make.
+type cacheKeyInputs struct {
+	ModulePath    string // blank in GOPATH mode
+	ModuleVersion string // blank for local copies of docket
+	GoSum         string // hash of the current module's go.sum
+	GoVersion     string // output of `go version`
+	GoFlags       string // GOFLAGS, which can set -tags and other build flags
+	GOOS          string
+	GOARCH        string
+	SourceHash    string // hash of docket's sources (only without a ModuleVersion)
+}
+
+func (in cacheKeyInputs) key() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "path=%s\nversion=%s\ngo.sum=%s\ngo=%s\nflags=%s\nos=%s\narch=%s\nsource=%s\n",
+		in.ModulePath, in.ModuleVersion, in.GoSum, in.GoVersion, in.GoFlags, in.GOOS, in.GOARCH,
+		in.SourceHash)
+
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// findCacheKeyInputs finds the version of dkt that `go build` would build and the rest of
+// cacheKeyInputs.
+func findCacheKeyInputs(mainPkg goListInfo) (cacheKeyInputs, error) {
+	var in cacheKeyInputs
+
+	goVersion, err := exec.Command("go", "version").Output()
+	if err != nil {
+		return in, fmt.Errorf("failed go version: %w", err)
+	}
+	in.GoVersion = strings.TrimSpace(string(goVersion))
+
+	goEnv, err := exec.Command("go", "env", "GOFLAGS", "GOOS", "GOARCH").Output()
+	if err != nil {
+		return in, fmt.Errorf("failed go env: %w", err)
+	}
+	if env := strings.Split(strings.TrimRight(string(goEnv), "\n"), "\n"); len(env) == 3 {
+		in.GoFlags, in.GOOS, in.GOARCH = env[0], env[1], env[2]
+	}
+
+	goMod, err := exec.Command("go", "env", "GOMOD").Output()
+	if err != nil {
+		return in, fmt.Errorf("failed go env GOMOD: %w", err)
+	}
+	if gm := strings.TrimSpace(string(goMod)); gm != "" && gm != os.DevNull {
+		goSum := filepath.Join(filepath.Dir(gm), "go.sum")
+		if in.GoSum, err = hashFiles([]string{goSum}); err != nil {
+			return in, err
+		}
+	}
+
+	// docket's root directory is two levels above dkt/main.
+	sourceDir := filepath.Dir(filepath.Dir(mainPkg.Dir))
+
+	if mod := mainPkg.Module; mod != nil {
+		in.ModulePath = mod.Path
+		in.ModuleVersion = mod.Version
+		if mod.Replace != nil {
+			in.ModulePath = mod.Replace.Path
+			in.ModuleVersion = mod.Replace.Version
+		}
+		if mod.Main {
+			in.ModuleVersion = ""
+		}
+	}
+
+	if in.ModuleVersion == "" {
+		if in.SourceHash, err = hashSources(sourceDir); err != nil {
+			return in, err
+		}
+	}
+
+	return in, nil
+}
+
+// hashSources hashes the files under dir that go into dkt's executable.
+func hashSources(dir string) (string, error) {
+	var files []string
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name := info.Name()
+		if info.IsDir() {
+			if path != dir && (name == "testdata" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if (strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go")) ||
+			name == "go.mod" || name == "go.sum" {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to find docket's sources: %w", err)
+	}
+
+	return hashFiles(files)
+}
+
+// hashFiles hashes the names and contents of files. Missing files are hashed as empty.
+func hashFiles(files []string) (string, error) {
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, f := range files {
+		fmt.Fprintf(h, "%s\n", filepath.ToSlash(f))
+
+		file, err := os.Open(f)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", fmt.Errorf("failed to hash %s: %w", f, err)
+		}
+
+		_, err = io.Copy(h, file)
+		file.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to hash %s: %w", f, err)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// dktCache holds dkt executables in the user's cache directory.
+type dktCache struct {
+	dir string
+}
+
+// cacheKeep is how many dkt executables the cache keeps. Changes to docket's sources, go.sum, or
+// the toolchain each add an executable, so the least recently used ones are pruned.
+const cacheKeep = 5
+
+func newDktCache() (dktCache, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return dktCache{}, fmt.Errorf("failed to find user cache dir: %w", err)
+	}
+
+	return dktCache{dir: filepath.Join(cacheDir, "docket", "dkt")}, nil
+}
+
+// executable returns the path of the cached executable for key, calling build to build it if
+// it's missing or if rebuild is true.
+//
+// build must return a temporary file inside the directory it's given, which is renamed into
+// place so that other dkt processes never see a partially-written executable.
+func (c dktCache) executable(
+	key string, rebuild bool, build func(dir string) (string, error),
+) (path string, built bool, err error) {
+	dir := filepath.Join(c.dir, key)
+	path = filepath.Join(dir, "dkt")
+	if runtime.GOOS == "windows" {
+		path += ".exe"
+	}
+
+	if !rebuild {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			c.markUsed(key)
+
+			return path, false, nil
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil { //nolint:gosec // not secret
+		return "", false, fmt.Errorf("failed to create cache dir: %w", err)
+	}
+
+	tempPath, err := build(dir)
+	if err != nil {
+		return "", false, err
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+
+		return "", false, fmt.Errorf("failed to move dkt into the cache: %w", err)
+	}
+
+	c.markUsed(key)
+	c.prune(key)
+
+	return path, true, nil
+}
+
+// markUsed records that key's executable was just used, by setting its directory's
+// modification time, which prune uses to find the least recently used executables.
+func (c dktCache) markUsed(key string) {
+	now := time.Now()
+	_ = os.Chtimes(filepath.Join(c.dir, key), now, now)
+}
+
+// prune removes all but the cacheKeep most recently used executables, never removing key's.
+//
+// Pruning is best effort, so it ignores errors. (An executable that another dkt process is
+// running can't be removed on Windows, for example.)
+func (c dktCache) prune(key string) {
+	infos, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	entries := make([]os.FileInfo, 0, len(infos))
+	for _, info := range infos {
+		if info.IsDir() && info.Name() != key {
+			entries = append(entries, info)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ModTime().After(entries[j].ModTime())
+	})
+
+	for i, info := range entries {
+		if i+1 >= cacheKeep { // key's executable is kept too
+			_ = os.RemoveAll(filepath.Join(c.dir, info.Name()))
+		}
+	}
+}
+
+// buildCachedDkt returns the path to a cached dkt executable, building it if necessary.
+func buildCachedDkt(
+	ctx context.Context, cache dktCache, mainPkg goListInfo, rebuild bool, trace io.Writer,
+) (string, error) {
+	in, err := findCacheKeyInputs(mainPkg)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(trace, "%scache key inputs: %+v\n", debugPrefix, in)
+
+	path, built, err := cache.executable(in.key(), rebuild, func(dir string) (string, error) {
+		return tempbuild.BuildInDir(ctx, dir, actualMainPkg, "dkt.*.tmp")
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if built {
+		fmt.Fprintf(trace, "%sbuilt %s\n", debugPrefix, path)
+	} else {
+		fmt.Fprintf(trace, "%susing cached %s\n", debugPrefix, path)
+	}
+
+	return path, nil
+}
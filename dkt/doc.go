@@ -17,6 +17,22 @@
 // Usage:
 //
 //     dkt [OPTIONS] [arguments to docker-compose...]
+//     dkt [OPTIONS] COMMAND [arguments...]
+//
+// Commands handled by dkt:
+//
+//     build [ARGS]          Build the mode's images the way docket does
+//     completion SHELL      Print a completion script for bash, zsh, or fish
+//     explain [--json] [SERVICE...]
+//                           Show the merged config and the file that set each field
+//     init [--force] TEMPLATE
+//                           Write docket files and a sample test from a template
+//     lint [--json]         Check the docket files for every mode
+//     lock [--check]        Pin every mode's images to digests in PREFIX.lock
+//     modes [--json]        List the modes in this directory and the files they use
+//     shell [SERVICE]       Open a shell in a service (default: the test service)
+//     test [--print] [ARGS] Run go test in the test service the way docket does
+//     watch [ARGS]          Run go test in the test service whenever files change
 //
 // Options:
 //
@@ -24,6 +40,10 @@
 //     -v, --version         Show version information
 //     -m, --mode=MODE       Set the docket mode (required) [$DOCKET_MODE]
 //     -P, --prefix=PREFIX   Set the docket prefix (default: docket) [$DOCKET_PREFIX]
+//     -C, --directory=DIR   Run in DIR instead of the current directory
+//         --all=PATTERN     Run a docker-compose command in every package matching
+//                           PATTERN (like ./...) that has docket files for the mode
+//         --rebuild         Rebuild dkt instead of using the cached executable
 //
 // See https://github.com/bloomberg/docket/tree/main/dkt for more documentation.
 //
@@ -0,0 +1,307 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bloomberg/docket/internal/compose"
+)
+
+// allCommands are the docker-compose commands that dkt --all can run. They don't need a
+// terminal, and their output still makes sense when it's interleaved with other packages'.
+var allCommands = map[string]bool{
+	"build": true, "config": true, "down": true, "images": true, "kill": true, "logs": true,
+	"ps": true, "pull": true, "restart": true, "start": true, "stop": true, "up": true,
+}
+
+// allPackage is a package that dkt --all runs in.
+type allPackage struct {
+	label   string // the package's directory, relative to the current directory
+	dir     string
+	project string // the docker-compose project, which other packages might share
+}
+
+// runInPackage runs dkt in a package's directory. It's a variable so that tests can replace
+// it.
+var runInPackage = func(
+	stdout, stderr io.Writer, pkg allPackage, opts options, args []string,
+) int {
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(stderr, "ERROR: failed to find dkt: %v\n", err)
+
+		return 1
+	}
+
+	cmdArgs := append([]string{"-C", pkg.dir, "-m", opts.Mode, "-P", opts.Prefix}, args...)
+	cmd := exec.Command(exe, cmdArgs...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+
+		fmt.Fprintf(stderr, "ERROR: failed to run %v: %v\n", cmd.Args, err)
+
+		return 1
+	}
+
+	return 0
+}
+
+// runAll runs a docker-compose command in each package matching opts.All that has docket
+// files for the mode. The packages run in parallel, each line of output starts with the
+// package's directory, and a summary of the exit codes comes last.
+//
+// Packages that share a docker-compose project run one after another, so that (for example)
+// their `up` commands don't race to create the same containers.
+func runAll(stdout, stderr io.Writer, opts options, args []string) int {
+	opts = withDefaultPrefix(opts)
+	if opts.Mode == "" {
+		fmt.Fprintf(stderr, "ERROR: use -m|--mode or set $DOCKET_MODE\n")
+
+		return 1
+	}
+
+	if !allCommands[args[0]] {
+		commands := make([]string, 0, len(allCommands))
+		for c := range allCommands {
+			commands = append(commands, c)
+		}
+		sort.Strings(commands)
+
+		fmt.Fprintf(stderr, "ERROR: --all can't run %q (use one of %s)\n",
+			args[0], strings.Join(commands, ", "))
+
+		return 1
+	}
+
+	pkgs, err := findPackagesWithMode(context.Background(), opts.All, opts.Prefix, opts.Mode)
+	if err != nil {
+		fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+		return 1
+	}
+	if len(pkgs) == 0 {
+		fmt.Fprintf(stderr, "ERROR: no packages matching %q have docket files for mode %q\n",
+			opts.All, opts.Mode)
+
+		return 1
+	}
+
+	// Each dkt gets the interrupt from the terminal and cleans up after itself.
+	signal.Ignore(os.Interrupt)
+
+	width := 0
+	for _, pkg := range pkgs {
+		if len(pkg.label) > width {
+			width = len(pkg.label)
+		}
+	}
+
+	var mu sync.Mutex
+	exitCodes := make([]int, len(pkgs))
+
+	var wg sync.WaitGroup
+	for _, group := range groupByProject(pkgs) {
+		wg.Add(1)
+
+		go func(group []int) {
+			defer wg.Done()
+
+			for _, i := range group {
+				pkg := pkgs[i]
+				prefix := fmt.Sprintf("%-*s | ", width, pkg.label)
+				pkgStdout := newPrefixWriter(&mu, stdout, prefix)
+				pkgStderr := newPrefixWriter(&mu, stderr, prefix)
+
+				exitCodes[i] = runInPackage(pkgStdout, pkgStderr, pkg, opts, args)
+
+				pkgStdout.Flush()
+				pkgStderr.Flush()
+			}
+		}(group)
+	}
+	wg.Wait()
+
+	return printAllSummary(stderr, pkgs, exitCodes)
+}
+
+// groupByProject returns the indexes of pkgs grouped by project, in the order the projects
+// first appear.
+func groupByProject(pkgs []allPackage) [][]int {
+	var groups [][]int
+	groupOf := map[string]int{}
+
+	for i, pkg := range pkgs {
+		g, ok := groupOf[pkg.project]
+		if !ok {
+			g = len(groups)
+			groupOf[pkg.project] = g
+			groups = append(groups, nil)
+		}
+
+		groups[g] = append(groups[g], i)
+	}
+
+	return groups
+}
+
+// printAllSummary prints each package's exit code and returns 1 if any of them failed.
+func printAllSummary(w io.Writer, pkgs []allPackage, exitCodes []int) int {
+	failed := 0
+	for _, code := range exitCodes {
+		if code != 0 {
+			failed++
+		}
+	}
+
+	fmt.Fprintf(w, "\ndkt --all: %d of %d packages failed\n", failed, len(pkgs))
+	for i, pkg := range pkgs {
+		status := "ok"
+		if exitCodes[i] != 0 {
+			status = fmt.Sprintf("exit %d", exitCodes[i])
+		}
+		fmt.Fprintf(w, "  %-8s %s\n", status, pkg.label)
+	}
+
+	if failed > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// findPackagesWithMode uses `go list` to find the directories of the packages matching pattern
+// and returns the ones with docket files for mode.
+func findPackagesWithMode(
+	ctx context.Context, pattern, prefix, mode string,
+) ([]allPackage, error) {
+	cmd := exec.CommandContext(ctx, "go", "list", "-e", "-f", "{{.Dir}}", pattern)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed go list %s: %w: %s", pattern, err, stderr.String())
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current dir: %w", err)
+	}
+
+	var pkgs []allPackage
+	for _, dir := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if dir == "" {
+			continue
+		}
+
+		modes, err := compose.FindModesInDir(dir, prefix)
+		if err != nil {
+			return nil, err
+		}
+
+		if !hasMode(modes, mode) {
+			continue
+		}
+
+		label, err := filepath.Rel(wd, dir)
+		if err != nil {
+			label = dir
+		}
+
+		pkgs = append(pkgs,
+			allPackage{label: label, dir: dir, project: compose.ProjectNameForDir(dir)})
+	}
+
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].label < pkgs[j].label })
+
+	return pkgs, nil
+}
+
+func hasMode(modes []compose.Mode, mode string) bool {
+	for _, m := range modes {
+		if m.Name == mode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// prefixWriter writes each line with a prefix. The writers for different packages share a
+// mutex so that their lines don't interleave.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	prefix string
+	buf    []byte // the current line, until it's complete
+}
+
+func newPrefixWriter(mu *sync.Mutex, w io.Writer, prefix string) *prefixWriter {
+	return &prefixWriter{mu: mu, w: w, prefix: prefix, buf: nil}
+}
+
+func (pw *prefixWriter) Write(p []byte) (int, error) {
+	pw.buf = append(pw.buf, p...)
+
+	for {
+		i := bytes.IndexByte(pw.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		if err := pw.writeLine(pw.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		pw.buf = pw.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the last line if it doesn't end with a newline.
+func (pw *prefixWriter) Flush() {
+	if len(pw.buf) > 0 {
+		_ = pw.writeLine(append(pw.buf, '\n'))
+		pw.buf = nil
+	}
+}
+
+func (pw *prefixWriter) writeLine(line []byte) error {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	_, err := fmt.Fprintf(pw.w, "%s%s", pw.prefix, line)
+
+	return err
+}
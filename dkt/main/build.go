@@ -0,0 +1,32 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"io"
+
+	"github.com/bloomberg/docket/internal/compose"
+)
+
+// runBuild runs `docker-compose build` with the mode's files and the options that docket's
+// build phase uses (DOCKET_BUILD_NO_CACHE and the rest). args come after those options.
+func runBuild(stdin io.Reader, stdout, stderr io.Writer, opts options, args []string) int {
+	return withCompose(stderr, opts, func(ctx context.Context, cmp *compose.Compose) int {
+		cmd := cmp.BuildCommand(ctx, compose.BuildOptionsFromEnv(), args...)
+
+		return runPassthrough(stdin, stdout, stderr, cmd)
+	})
+}
@@ -0,0 +1,310 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/bloomberg/docket/internal/compose"
+)
+
+// completeCommand is the hidden command that the completion scripts run to find candidates.
+const completeCommand = "__complete"
+
+// dktCommands are the commands that dkt handles itself.
+var dktCommands = []string{
+	"completion", "explain", "init", "lint", "lock", "modes", "shell", "test", "watch",
+}
+
+// composeCommands are docker-compose's commands.
+var composeCommands = []string{
+	"build", "config", "create", "down", "events", "exec", "help", "images", "kill", "logs",
+	"pause", "port", "ps", "pull", "push", "restart", "rm", "run", "scale", "start", "stop",
+	"top", "unpause", "up", "version",
+}
+
+// serviceCommands are the commands whose arguments include service names.
+var serviceCommands = map[string]bool{
+	"build": true, "create": true, "events": true, "exec": true, "explain": true,
+	"images": true, "kill": true, "logs": true, "pause": true, "port": true, "ps": true,
+	"pull": true, "push": true, "restart": true, "rm": true, "run": true, "shell": true,
+	"start": true, "stop": true, "top": true, "unpause": true, "up": true,
+}
+
+// runCompletion prints a shell completion script.
+func runCompletion(stdout, stderr io.Writer, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(stderr, "ERROR: usage: dkt completion bash|zsh|fish\n")
+
+		return 1
+	}
+
+	switch args[0] {
+	case "bash":
+		fmt.Fprint(stdout, bashCompletion)
+	case "zsh":
+		fmt.Fprint(stdout, zshCompletion)
+	case "fish":
+		fmt.Fprint(stdout, fishCompletion)
+	default:
+		fmt.Fprintf(stderr, "ERROR: unsupported shell %q (use bash, zsh, or fish)\n", args[0])
+
+		return 1
+	}
+
+	return 0
+}
+
+// runComplete prints completion candidates, one per line:
+//
+//     __complete modes              the modes in the current directory
+//     __complete commands           dkt's and docker-compose's commands
+//     __complete services COMMAND   the mode's services, if COMMAND takes service names
+func runComplete(stdout, stderr io.Writer, opts options, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintf(stderr, "ERROR: usage: dkt %s modes|commands|services [COMMAND]\n",
+			completeCommand)
+
+		return 1
+	}
+
+	var candidates []string
+
+	switch args[0] {
+	case "modes":
+		modes, err := compose.FindModes(opts.Prefix)
+		if err != nil {
+			fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+			return 1
+		}
+		for _, m := range modes {
+			candidates = append(candidates, m.Name)
+		}
+
+	case "commands":
+		candidates = append(candidates, dktCommands...)
+		candidates = append(candidates, composeCommands...)
+		sort.Strings(candidates)
+
+	case "services":
+		if opts.Mode == "" || len(args) < 2 || !serviceCommands[args[1]] {
+			return 0
+		}
+
+		services, err := compose.ServiceNames(opts.Prefix, opts.Mode)
+		if err != nil {
+			fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+			return 1
+		}
+		candidates = services
+
+	default:
+		fmt.Fprintf(stderr, "ERROR: unknown completion %q\n", args[0])
+
+		return 1
+	}
+
+	for _, c := range candidates {
+		fmt.Fprintln(stdout, c)
+	}
+
+	return 0
+}
+
+// The completion scripts find the mode, prefix, and command on the command line and ask
+// `dkt __complete` for the candidates.
+
+const bashCompletion = `# bash completion for dkt
+# Add this to ~/.bashrc:
+#   source <(dkt completion bash)
+
+_dkt() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local prev="${COMP_WORDS[COMP_CWORD-1]}"
+    local opts=() command="" word i
+
+    # bash splits --mode=MODE into three words: --mode, =, and MODE.
+    if [[ "$prev" == "=" ]]; then
+        prev="${COMP_WORDS[COMP_CWORD-2]}"
+    fi
+
+    for ((i = 1; i < COMP_CWORD; i++)); do
+        word="${COMP_WORDS[i]}"
+        case "$word" in
+            -m|--mode|-P|--prefix|-C|--directory)
+                if [[ "${COMP_WORDS[i+1]}" == "=" ]]; then
+                    i=$((i + 1))
+                fi
+                if ((i + 1 < COMP_CWORD)); then
+                    opts+=("$word" "${COMP_WORDS[i+1]}")
+                fi
+                i=$((i + 1))
+                ;;
+            --all)
+                if [[ "${COMP_WORDS[i+1]}" == "=" ]]; then
+                    i=$((i + 1))
+                fi
+                i=$((i + 1))
+                ;;
+            --mode=*|--prefix=*|--directory=*)
+                opts+=("$word")
+                ;;
+            -*)
+                ;;
+            *)
+                command="$word"
+                break
+                ;;
+        esac
+    done
+
+    case "$prev" in
+        -m|--mode)
+            COMPREPLY=($(compgen -W "$(dkt "${opts[@]}" __complete modes 2>/dev/null)" -- "$cur"))
+            return
+            ;;
+        -C|--directory)
+            COMPREPLY=($(compgen -d -- "$cur"))
+            return
+            ;;
+        -P|--prefix|--all)
+            return
+            ;;
+    esac
+
+    if [[ -z "$command" && "$cur" == -* ]]; then
+        COMPREPLY=($(compgen -W "-h --help -v --version -m --mode -P --prefix -C --directory --all --rebuild" -- "$cur"))
+    elif [[ -z "$command" ]]; then
+        COMPREPLY=($(compgen -W "$(dkt "${opts[@]}" __complete commands 2>/dev/null)" -- "$cur"))
+    else
+        COMPREPLY=($(compgen -W "$(dkt "${opts[@]}" __complete services "$command" 2>/dev/null)" -- "$cur"))
+    fi
+}
+
+complete -F _dkt dkt
+`
+
+const zshCompletion = `# zsh completion for dkt
+# Add this to ~/.zshrc (after compinit):
+#   source <(dkt completion zsh)
+
+_dkt() {
+    local -a opts candidates
+    local command="" word i
+
+    for ((i = 2; i < CURRENT; i++)); do
+        word="${words[i]}"
+        case "$word" in
+            -m|--mode|-P|--prefix|-C|--directory)
+                if ((i + 1 < CURRENT)); then
+                    opts+=("$word" "${words[i+1]}")
+                fi
+                ((i++))
+                ;;
+            --all)
+                ((i++))
+                ;;
+            --mode=*|--prefix=*|--directory=*)
+                opts+=("$word")
+                ;;
+            -*)
+                ;;
+            *)
+                command="$word"
+                break
+                ;;
+        esac
+    done
+
+    case "${words[CURRENT-1]}" in
+        -m|--mode)
+            candidates=(${(f)"$(dkt "${opts[@]}" __complete modes 2>/dev/null)"})
+            compadd -a candidates
+            return
+            ;;
+        -C|--directory)
+            _files -/
+            return
+            ;;
+        -P|--prefix|--all)
+            return
+            ;;
+    esac
+
+    if [[ -z "$command" && "${words[CURRENT]}" == --mode=* ]]; then
+        candidates=(${(f)"$(dkt "${opts[@]}" __complete modes 2>/dev/null)"})
+        compadd -P --mode= -a candidates
+    elif [[ -z "$command" && "${words[CURRENT]}" == -* ]]; then
+        compadd -- -h --help -v --version -m --mode -P --prefix -C --directory --all --rebuild
+    elif [[ -z "$command" ]]; then
+        candidates=(${(f)"$(dkt "${opts[@]}" __complete commands 2>/dev/null)"})
+        compadd -a candidates
+    else
+        candidates=(${(f)"$(dkt "${opts[@]}" __complete services "$command" 2>/dev/null)"})
+        compadd -a candidates
+    fi
+}
+
+compdef _dkt dkt
+`
+
+const fishCompletion = `# fish completion for dkt
+# Add this to ~/.config/fish/config.fish:
+#   dkt completion fish | source
+
+function __dkt_complete
+    set -l tokens (commandline -opc)
+    set -e tokens[1]
+    set -l opts
+    set -l command
+    while set -q tokens[1]
+        switch $tokens[1]
+            case -m --mode -P --prefix -C --directory
+                set opts $opts $tokens[1] $tokens[2]
+                set -e tokens[1]
+            case --all
+                set -e tokens[1]
+            case '--mode=*' '--prefix=*' '--directory=*'
+                set opts $opts $tokens[1]
+            case '-*'
+            case '*'
+                set command $tokens[1]
+                break
+        end
+        set -e tokens[1]
+    end
+
+    if test "$argv[1]" = modes
+        dkt $opts __complete modes 2>/dev/null
+    else if test -z "$command"
+        dkt $opts __complete commands 2>/dev/null
+    else
+        dkt $opts __complete services $command 2>/dev/null
+    end
+end
+
+complete -c dkt -f -a '(__dkt_complete)'
+complete -c dkt -s h -l help -d 'Show help'
+complete -c dkt -s v -l version -d 'Show version information'
+complete -c dkt -s m -l mode -x -a '(__dkt_complete modes)' -d 'Set the docket mode'
+complete -c dkt -s P -l prefix -x -d 'Set the docket prefix'
+complete -c dkt -s C -l directory -x -a '(__fish_complete_directories)' -d 'Run in a directory'
+complete -c dkt -l all -x -d 'Run in every package matching a pattern'
+complete -c dkt -l rebuild -d 'Rebuild dkt instead of using the cached executable'
+`
@@ -38,6 +38,8 @@ import (
 type options struct {
 	Mode   string
 	Prefix string
+	Dir    string // -C: the directory to run in
+	All    string // --all: the package pattern to run in
 
 	Version bool
 	Help    bool
@@ -65,6 +67,16 @@ func run(envMode, envPrefix string, stdin io.Reader, stdout, stderr io.Writer, a
 		opts.Prefix = envPrefix
 	}
 
+	if opts.Dir != "" {
+		restore, err := chdir(opts.Dir)
+		if err != nil {
+			fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+			return 1
+		}
+		defer restore()
+	}
+
 	switch {
 	case opts.Help:
 		return printHelp(stdout)
@@ -72,6 +84,8 @@ func run(envMode, envPrefix string, stdin io.Reader, stdout, stderr io.Writer, a
 		return printVersions(stdout)
 	case len(remainingArgs) == 0:
 		return printHelp(stdout)
+	case opts.All != "":
+		return runAll(stdout, stderr, opts, remainingArgs)
 	default:
 		return runDockerCompose(stdin, stdout, stderr, opts, remainingArgs)
 	}
@@ -95,6 +109,8 @@ func parseArgs(args []string) (options, []string, error) {
 		case arg == "-v", arg == "--version":
 			opts.Version = true
 
+		case arg == "--rebuild": // handled by the runner in ../dkt
+
 		case arg == "-m", arg == "--mode": // -m NAME or --mode NAME
 			if i+1 >= len(args) {
 				return opts, nil, missingParamForOptionError(arg)
@@ -117,6 +133,26 @@ func parseArgs(args []string) (options, []string, error) {
 		case strings.HasPrefix(arg, "--prefix="): // --prefix=NAME
 			opts.Prefix = arg[len("--prefix="):]
 
+		case arg == "-C", arg == "--directory": // -C DIR or --directory DIR
+			if i+1 >= len(args) {
+				return opts, nil, missingParamForOptionError(arg)
+			}
+			opts.Dir = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "-C"): // -CDIR
+			opts.Dir = arg[len("-C"):]
+		case strings.HasPrefix(arg, "--directory="): // --directory=DIR
+			opts.Dir = arg[len("--directory="):]
+
+		case arg == "--all": // --all PATTERN
+			if i+1 >= len(args) {
+				return opts, nil, missingParamForOptionError(arg)
+			}
+			opts.All = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--all="): // --all=PATTERN
+			opts.All = arg[len("--all="):]
+
 		default:
 			return opts, args[i:], nil
 		}
@@ -134,17 +170,37 @@ Any arguments that aren't dkt-specific will be passed through to docker-compose.
 
 Usage:
   dkt [OPTIONS] [arguments to docker-compose...]
+  dkt [OPTIONS] COMMAND [arguments...]
 
 Examples:
   dkt config
   dkt up -d
   dkt down
+  dkt modes
+
+Commands handled by dkt:
+  build [ARGS]          Build the mode's images the way docket does
+  completion SHELL      Print a completion script for bash, zsh, or fish
+  explain [--json] [SERVICE...]
+                        Show the merged config and the file that set each field
+  init [--force] TEMPLATE
+                        Write docket files and a sample test from a template
+  lint [--json]         Check the docket files for every mode
+  lock [--check]        Pin every mode's images to digests in PREFIX.lock
+  modes [--json]        List the modes in this directory and the files they use
+  shell [SERVICE]       Open a shell in a service (default: the test service)
+  test [--print] [ARGS] Run go test in the test service the way docket does
+  watch [ARGS]          Run go test in the test service whenever files change
 
 Options:
   -h, --help            Show this help
   -v, --version         Show version information
   -m, --mode=MODE       Set the docket mode (required) [$DOCKET_MODE]
   -P, --prefix=PREFIX   Set the docket prefix (default: docket) [$DOCKET_PREFIX]
+  -C, --directory=DIR   Run in DIR instead of the current directory
+      --all=PATTERN     Run a docker-compose command in every package matching
+                        PATTERN (like ./...) that has docket files for the mode
+      --rebuild         Rebuild dkt instead of using the cached executable
 
 Output of 'docker-compose help'
 -------------------------------
@@ -187,11 +243,66 @@ func runDockerCompose(
 
 		return runDockerComposeDirectly(stdin, stdout, stderr, remainingArgs...)
 
+	case "build":
+		return runBuild(stdin, stdout, stderr, opts, remainingArgs[1:])
+
+	case "completion":
+		return runCompletion(stdout, stderr, remainingArgs[1:])
+
+	case completeCommand:
+		return runComplete(stdout, stderr, withDefaultPrefix(opts), remainingArgs[1:])
+
+	case "explain":
+		return runExplain(stdout, stderr, opts, remainingArgs[1:])
+
+	case "init":
+		return runInit(stdout, stderr, withDefaultPrefix(opts), remainingArgs[1:])
+
+	case "lint":
+		return runLint(stdout, stderr, withDefaultPrefix(opts), remainingArgs[1:])
+
+	case "lock":
+		return runLock(stdout, stderr, withDefaultPrefix(opts), remainingArgs[1:])
+
+	case "modes":
+		return runModes(stdout, stderr, withDefaultPrefix(opts), remainingArgs[1:])
+
+	case "shell":
+		return runShell(stdin, stdout, stderr, opts, remainingArgs[1:])
+
+	case "test":
+		return runTest(stdin, stdout, stderr, opts, remainingArgs[1:])
+
+	case "watch":
+		return runWatch(stdout, stderr, opts, remainingArgs[1:])
+
 	default:
 		return useDocket(stdin, stdout, stderr, opts, remainingArgs)
 	}
 }
 
+// chdir changes the current directory to dir and returns a function that changes it back.
+func chdir(dir string) (func(), error) {
+	oldDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current dir: %w", err)
+	}
+
+	if err := os.Chdir(dir); err != nil {
+		return nil, fmt.Errorf("failed to change dir: %w", err)
+	}
+
+	return func() { _ = os.Chdir(oldDir) }, nil
+}
+
+func withDefaultPrefix(opts options) options {
+	if opts.Prefix == "" {
+		opts.Prefix = "docket"
+	}
+
+	return opts
+}
+
 func runDockerComposeDirectly(stdin io.Reader, stdout, stderr io.Writer, args ...string) int {
 	cmd := exec.Command("docker-compose", args...)
 	cmd.Stdin = stdin
@@ -213,9 +324,16 @@ func runDockerComposeDirectly(stdin io.Reader, stdout, stderr io.Writer, args ..
 func useDocket(
 	stdin io.Reader, stdout, stderr io.Writer, opts options, remainingArgs []string,
 ) int {
-	if opts.Prefix == "" {
-		opts.Prefix = "docket"
-	}
+	return withCompose(stderr, opts, func(ctx context.Context, cmp *compose.Compose) int {
+		return runPassthrough(stdin, stdout, stderr, cmp.Command(ctx, remainingArgs...))
+	})
+}
+
+// withCompose sets up docket for the mode and prefix in opts, calls f, and cleans up.
+func withCompose(
+	stderr io.Writer, opts options, f func(context.Context, *compose.Compose) int,
+) int {
+	opts = withDefaultPrefix(opts)
 	if opts.Mode == "" {
 		fmt.Fprintf(stderr, "ERROR: use -m|--mode or set $DOCKET_MODE\n")
 
@@ -235,9 +353,11 @@ func useDocket(
 		}
 	}()
 
-	cmd := cmp.Command(ctx, remainingArgs...)
+	return f(ctx, cmp)
+}
 
-	// passthrough
+// runPassthrough runs cmd with our stdin, stdout, and stderr and returns its exit code.
+func runPassthrough(stdin io.Reader, stdout, stderr io.Writer, cmd *exec.Cmd) int {
 	cmd.Stdin = stdin
 	cmd.Stdout = stdout
 	cmd.Stderr = stderr
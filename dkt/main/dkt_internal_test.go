@@ -16,10 +16,17 @@ package main
 
 import (
 	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/bloomberg/docket/internal/compose"
 	"github.com/bloomberg/go-testgroup"
 )
 
@@ -133,6 +140,403 @@ func (grp *dktTests) ComposeFailure(t *testgroup.T) {
 	t.Contains(stderr.String(), "No such command")
 }
 
+func (grp *dktTests) Modes(t *testgroup.T) {
+	t.Require.NoError(os.Chdir("testdata"))
+	defer func() {
+		t.NoError(os.Chdir(".."))
+	}()
+
+	t.Run("text", func(t *testgroup.T) {
+		var stdout, stderr strings.Builder
+		exitCode := run("", "", nil, &stdout, &stderr, "modes")
+
+		t.Zero(exitCode)
+		t.Equal("good\n  docket.good.yaml\n", stdout.String())
+		t.Empty(stderr.String())
+	})
+
+	t.Run("json", func(t *testgroup.T) {
+		var stdout, stderr strings.Builder
+		exitCode := run("", "", nil, &stdout, &stderr, "modes", "--json")
+
+		t.Zero(exitCode)
+		t.JSONEq(`[{"mode": "good", "files": ["docket.good.yaml"]}]`, stdout.String())
+		t.Empty(stderr.String())
+	})
+
+	t.Run("other prefix", func(t *testgroup.T) {
+		var stdout, stderr strings.Builder
+		exitCode := run("", "", nil, &stdout, &stderr, "--prefix=none", "modes")
+
+		t.Zero(exitCode)
+		t.Empty(stdout.String())
+		t.Empty(stderr.String())
+	})
+
+	t.Run("bad argument", func(t *testgroup.T) {
+		var stdout, stderr strings.Builder
+		exitCode := run("", "", nil, &stdout, &stderr, "modes", "--bad")
+
+		t.NotZero(exitCode)
+		t.Contains(stderr.String(), "ERROR")
+	})
+}
+
+func (grp *dktTests) Lint(t *testgroup.T) {
+	t.Require.NoError(os.Chdir("testdata"))
+	defer func() {
+		t.NoError(os.Chdir(".."))
+	}()
+
+	t.Run("text", func(t *testgroup.T) {
+		var stdout, stderr strings.Builder
+		exitCode := run("", "", nil, &stdout, &stderr, "lint")
+
+		t.Zero(exitCode)
+		t.Empty(stdout.String())
+		t.Empty(stderr.String())
+	})
+
+	t.Run("json", func(t *testgroup.T) {
+		var stdout, stderr strings.Builder
+		exitCode := run("", "", nil, &stdout, &stderr, "lint", "--json")
+
+		t.Zero(exitCode)
+		t.JSONEq(`[]`, stdout.String())
+		t.Empty(stderr.String())
+	})
+
+	t.Run("bad argument", func(t *testgroup.T) {
+		var stdout, stderr strings.Builder
+		exitCode := run("", "", nil, &stdout, &stderr, "lint", "--bad")
+
+		t.NotZero(exitCode)
+		t.Contains(stderr.String(), "ERROR")
+	})
+}
+
+func (grp *dktTests) FormatLintProblem(t *testgroup.T) {
+	t.Equal("docket.yaml: bob: error: the service has no image or build (modes: a, b)",
+		formatLintProblem(compose.LintProblem{
+			Severity: compose.LintError,
+			Modes:    []string{"a", "b"},
+			File:     "docket.yaml",
+			Service:  "bob",
+			Message:  "the service has no image or build",
+		}))
+	t.Equal("docket.x: warning: unused",
+		formatLintProblem(compose.LintProblem{
+			Severity: compose.LintWarning,
+			Modes:    nil,
+			File:     "docket.x",
+			Service:  "",
+			Message:  "unused",
+		}))
+}
+
+func (grp *dktTests) FormatLockDrift(t *testgroup.T) {
+	t.Equal("docket.lock: redis:6: not in the lock file (modes: a, b)",
+		formatLockDrift("docket.lock", compose.LockDrift{
+			Image:   "redis:6",
+			Modes:   []string{"a", "b"},
+			Message: "not in the lock file",
+		}))
+	t.Equal("docket.lock: redis:5: no mode uses it",
+		formatLockDrift("docket.lock", compose.LockDrift{
+			Image:   "redis:5",
+			Modes:   nil,
+			Message: "no mode uses it",
+		}))
+}
+
+func (grp *dktTests) ShellTooManyArguments(t *testgroup.T) {
+	var stdout, stderr strings.Builder
+	exitCode := run("", "", nil, &stdout, &stderr, "--mode=good", "shell", "a", "b")
+
+	t.NotZero(exitCode)
+	t.Empty(stdout.String())
+	t.Contains(stderr.String(), "too many arguments")
+}
+
+func (grp *dktTests) ParseTestArgs(t *testgroup.T) {
+	printOnly, runArg, testArgs, err := parseTestArgs(
+		[]string{"-v", "-run", "TestFoo/bar", "-count=1"})
+	t.NoError(err)
+	t.False(printOnly)
+	t.Equal("^TestFoo$/^bar$", runArg)
+	t.Equal([]string{"-v", "-count=1"}, testArgs)
+
+	printOnly, runArg, testArgs, err = parseTestArgs([]string{"--print", "-run=Test.*"})
+	t.NoError(err)
+	t.True(printOnly)
+	t.Equal("Test.*", runArg)
+	t.Equal([]string{}, testArgs)
+
+	printOnly, runArg, testArgs, err = parseTestArgs([]string{"-args", "-run", "x"})
+	t.NoError(err)
+	t.False(printOnly)
+	t.Equal("", runArg)
+	t.Equal([]string{"-args", "-run", "x"}, testArgs)
+
+	_, _, _, err = parseTestArgs([]string{"-v", "-run"})
+	t.Equal(missingParamForOptionError("-run"), err)
+}
+
+func (grp *dktTests) ShellJoin(t *testgroup.T) {
+	t.Equal("go test -run '^TestFoo$' -v",
+		shellJoin([]string{"go", "test", "-run", "^TestFoo$", "-v"}))
+	t.Equal(`'it'\''s' ''`, shellJoin([]string{"it's", ""}))
+}
+
+func (grp *dktTests) PrintExplanation(t *testgroup.T) {
+	explained := []compose.ExplainedService{
+		{Name: "redis", Fields: []compose.ExplainedField{
+			{Field: "image", Value: "redis", File: "docket.yaml", Generated: false},
+		}},
+		{Name: "tester", Fields: []compose.ExplainedField{
+			{Field: "command", Value: []interface{}{"sleep", "1"}, File: "docket.yaml", Generated: false},
+			{Field: "working_dir", Value: "/go", File: "mounts.yaml", Generated: true},
+		}},
+	}
+
+	var out strings.Builder
+	printExplanation(&out, explained)
+	t.Equal(`redis:
+  image  redis  docket.yaml
+
+tester:
+  command      ["sleep","1"]  docket.yaml
+  working_dir  /go            mounts.yaml (generated)
+`, out.String())
+
+	selected, err := selectServices(explained, []string{"tester"})
+	t.NoError(err)
+	t.Equal(explained[1:], selected)
+
+	_, err = selectServices(explained, []string{"missing"})
+	t.Error(err)
+}
+
+func (grp *dktTests) WatchSnapshots(t *testgroup.T) {
+	t.Require.NoError(os.Chdir("testdata"))
+	defer func() {
+		t.NoError(os.Chdir(".."))
+	}()
+
+	snap, err := snapshotWatchedFiles("docket")
+	t.Require.NoError(err)
+	t.Len(snap, 2)
+	t.Contains(snap, "docket.good.yaml")
+	t.Contains(snap, "empty.go")
+
+	t.Empty(diffSnapshots(snap, snap))
+
+	now := time.Now()
+	before := watchSnapshot{"a.go": now, "b.go": now, "docket.yaml": now}
+	after := watchSnapshot{"a.go": now.Add(time.Second), "c.go": now, "docket.yaml": now}
+	t.Equal(watchChanges{goFiles: []string{"a.go", "b.go", "c.go"}, docketFiles: nil},
+		diffSnapshots(before, after))
+
+	t.True(isDocketFile("docket", "docket.full.yml"))
+	t.False(isDocketFile("docket", "docket-source-mounts.1.yaml"))
+	t.False(isDocketFile("docket", "docket.full.yaml.orig"))
+}
+
+func (grp *dktTests) Init(t *testgroup.T) {
+	oldDir, err := os.Getwd()
+	t.Require.NoError(err)
+
+	dir, err := ioutil.TempDir("", "docket-init-test.")
+	t.Require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	t.Require.NoError(os.Chdir(dir))
+	defer func() {
+		t.NoError(os.Chdir(oldDir))
+	}()
+
+	var stdout, stderr strings.Builder
+	exitCode := run("", "", nil, &stdout, &stderr, "init", "redis")
+	t.Zero(exitCode, stderr.String())
+	t.Contains(stdout.String(), "wrote docket.debug.yaml")
+
+	full, err := ioutil.ReadFile("docket.full.yaml")
+	t.Require.NoError(err)
+	t.Contains(string(full), "image: golang:1\n")
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = run("", "", nil, &stdout, &stderr, "init", "hello")
+	t.NotZero(exitCode)
+	t.Contains(stderr.String(), "not overwriting docket.yaml, docket.full.yaml, docket_test.go")
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = run("", "", nil, &stdout, &stderr, "init", "--force", "hello")
+	t.Zero(exitCode, stderr.String())
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = run("", "", nil, &stdout, &stderr, "init", "nope")
+	t.NotZero(exitCode)
+	t.Contains(stderr.String(), "service-under-test")
+}
+
+func (grp *dktTests) InitTemplates(t *testgroup.T) {
+	data := initData{Prefix: "docket", Package: "example", GoImage: "golang:1.21"}
+
+	for name, tmpl := range initTemplates {
+		files, err := renderInitFiles(tmpl, data)
+		t.Require.NoError(err, name)
+
+		for _, f := range files {
+			if filepath.Ext(f.name) != ".go" {
+				continue
+			}
+			_, err := parser.ParseFile(token.NewFileSet(), f.name, f.contents, 0)
+			t.NoError(err, "%s: %s", name, f.name)
+		}
+	}
+
+	t.Equal("golang:1", goImage(""))
+	t.Equal("golang:1.21", goImage("1.21"))
+}
+
+func (grp *dktTests) Completion(t *testgroup.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		var stdout, stderr strings.Builder
+		exitCode := run("", "", nil, &stdout, &stderr, "completion", shell)
+
+		t.Zero(exitCode, shell)
+		t.Contains(stdout.String(), "__complete", shell)
+		t.Empty(stderr.String(), shell)
+	}
+
+	var stdout, stderr strings.Builder
+	exitCode := run("", "", nil, &stdout, &stderr, "completion", "tcsh")
+	t.NotZero(exitCode)
+	t.Contains(stderr.String(), "unsupported shell")
+}
+
+func (grp *dktTests) Complete(t *testgroup.T) {
+	t.Require.NoError(os.Chdir("testdata"))
+	defer func() {
+		t.NoError(os.Chdir(".."))
+	}()
+
+	t.Run("modes", func(t *testgroup.T) {
+		var stdout, stderr strings.Builder
+		exitCode := run("", "", nil, &stdout, &stderr, "__complete", "modes")
+
+		t.Zero(exitCode)
+		t.Equal("good\n", stdout.String())
+	})
+
+	t.Run("commands", func(t *testgroup.T) {
+		var stdout, stderr strings.Builder
+		exitCode := run("", "", nil, &stdout, &stderr, "__complete", "commands")
+
+		t.Zero(exitCode)
+		t.Contains(stdout.String(), "\nshell\n")
+		t.Contains(stdout.String(), "\nup\n")
+	})
+
+	t.Run("services without a mode", func(t *testgroup.T) {
+		var stdout, stderr strings.Builder
+		exitCode := run("", "", nil, &stdout, &stderr, "__complete", "services", "up")
+
+		t.Zero(exitCode)
+		t.Empty(stdout.String())
+	})
+
+	t.Run("services", func(t *testgroup.T) {
+		var stdout, stderr strings.Builder
+		exitCode := run("", "", nil, &stdout, &stderr, "-m", "good", "__complete", "services", "up")
+
+		t.Zero(exitCode)
+		t.Empty(stdout.String())
+		t.Empty(stderr.String())
+	})
+}
+
+func (grp *dktTests) Directory(t *testgroup.T) {
+	var stdout, stderr strings.Builder
+	exitCode := run("", "", nil, &stdout, &stderr, "-C", "testdata", "modes")
+
+	t.Zero(exitCode)
+	t.Contains(stdout.String(), "good")
+
+	_, err := os.Stat("dkt.go")
+	t.NoError(err, "should be back in the original directory")
+
+	exitCode = run("", "", nil, &stdout, &stderr, "-C", "no-such-dir", "modes")
+	t.NotZero(exitCode)
+	t.Contains(stderr.String(), "failed to change dir")
+}
+
+func (grp *dktTests) All(t *testgroup.T) {
+	oldRunInPackage := runInPackage
+	defer func() { runInPackage = oldRunInPackage }()
+
+	var ran []string
+	runInPackage = func(
+		stdout, stderr io.Writer, pkg allPackage, opts options, args []string,
+	) int {
+		ran = append(ran, pkg.label)
+		fmt.Fprintf(stdout, "%s %s\npartial", opts.Mode, strings.Join(args, " "))
+
+		return 3
+	}
+
+	var stdout, stderr strings.Builder
+	exitCode := run("", "", nil, &stdout, &stderr, "--all", "./testdata", "-m", "good", "ps")
+
+	t.Equal(1, exitCode)
+	t.Equal([]string{"testdata"}, ran)
+	t.Equal("testdata | good ps\ntestdata | partial\n", stdout.String())
+	t.Contains(stderr.String(), "1 of 1 packages failed")
+	t.Contains(stderr.String(), "exit 3   testdata")
+
+	t.Run("no matching packages", func(t *testgroup.T) {
+		var stdout, stderr strings.Builder
+		exitCode := run("", "", nil, &stdout, &stderr, "--all", "./testdata", "-m", "other", "ps")
+
+		t.NotZero(exitCode)
+		t.Contains(stderr.String(), "no packages")
+	})
+
+	t.Run("unsupported command", func(t *testgroup.T) {
+		var stdout, stderr strings.Builder
+		exitCode := run("", "", nil, &stdout, &stderr, "--all", "./testdata", "-m", "good", "exec")
+
+		t.NotZero(exitCode)
+		t.Contains(stderr.String(), "can't run")
+	})
+}
+
+func (grp *dktTests) GroupByProject(t *testgroup.T) {
+	pkgs := []allPackage{
+		{label: "a/redis", dir: "/a/redis", project: "redis"},
+		{label: "b", dir: "/b", project: "b"},
+		{label: "c/redis", dir: "/c/redis", project: "redis"},
+	}
+
+	t.Equal([][]int{{0, 2}, {1}}, groupByProject(pkgs))
+	t.Empty(groupByProject(nil))
+}
+
+func (grp *dktTests) PrintAllSummary(t *testgroup.T) {
+	pkgs := []allPackage{{label: "a", dir: "/a"}, {label: "b/c", dir: "/b/c"}}
+
+	var w strings.Builder
+	t.Zero(printAllSummary(&w, pkgs, []int{0, 0}))
+	t.Equal("\ndkt --all: 0 of 2 packages failed\n  ok       a\n  ok       b/c\n", w.String())
+
+	w.Reset()
+	t.Equal(1, printAllSummary(&w, pkgs, []int{0, 2}))
+	t.Contains(w.String(), "  exit 2   b/c\n")
+}
+
 func (grp *dktTests) ModeRequired(t *testgroup.T) {
 	var stdout, stderr strings.Builder
 	exitCode := run("", "", nil, &stdout, &stderr, "config")
@@ -180,7 +584,7 @@ func (grp *modeAndPrefixTests) ArgsOverrideEnv(t *testgroup.T) {
 }
 
 func (grp *modeAndPrefixTests) MissingArguments(t *testgroup.T) {
-	testcases := []string{"-m", "--mode", "-P", "--prefix"}
+	testcases := []string{"-m", "--mode", "-P", "--prefix", "-C", "--directory", "--all"}
 
 	for _, tc := range testcases {
 		tc := tc
@@ -202,14 +606,25 @@ func (grp *modeAndPrefixTests) FlagVariations(t *testgroup.T) {
 		expectedOpts options
 	}{
 		{
-			short:        "m",
-			long:         "mode",
-			expectedOpts: options{Mode: "VALUE", Prefix: "", Version: false, Help: false},
+			short: "m",
+			long:  "mode",
+			expectedOpts: options{
+				Mode: "VALUE", Prefix: "", Dir: "", All: "", Version: false, Help: false,
+			},
+		},
+		{
+			short: "P",
+			long:  "prefix",
+			expectedOpts: options{
+				Mode: "", Prefix: "VALUE", Dir: "", All: "", Version: false, Help: false,
+			},
 		},
 		{
-			short:        "P",
-			long:         "prefix",
-			expectedOpts: options{Mode: "", Prefix: "VALUE", Version: false, Help: false},
+			short: "C",
+			long:  "directory",
+			expectedOpts: options{
+				Mode: "", Prefix: "", Dir: "VALUE", All: "", Version: false, Help: false,
+			},
 		},
 	}
 
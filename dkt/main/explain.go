@@ -0,0 +1,164 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bloomberg/docket/internal/compose"
+	"github.com/fatih/color"
+)
+
+type explainedFieldJSON struct {
+	Service   string      `json:"service"`
+	Field     string      `json:"field"`
+	Value     interface{} `json:"value"`
+	File      string      `json:"file"`
+	Generated bool        `json:"generated"`
+}
+
+// runExplain shows the merged configuration of the services with the file that set each field.
+func runExplain(stdout, stderr io.Writer, opts options, args []string) int {
+	asJSON := false
+	var services []string
+	for _, arg := range args {
+		switch {
+		case arg == "--json":
+			asJSON = true
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintf(stderr, "ERROR: unknown argument to explain: %q\n", arg)
+
+			return 1
+		default:
+			services = append(services, arg)
+		}
+	}
+
+	return withCompose(stderr, opts, func(ctx context.Context, cmp *compose.Compose) int {
+		explained, err := cmp.Explain()
+		if err != nil {
+			fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+			return 1
+		}
+
+		explained, err = selectServices(explained, services)
+		if err != nil {
+			fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+			return 1
+		}
+
+		if asJSON {
+			if err := printExplanationJSON(stdout, explained); err != nil {
+				fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+				return 1
+			}
+
+			return 0
+		}
+
+		printExplanation(stdout, explained)
+
+		return 0
+	})
+}
+
+func selectServices(
+	explained []compose.ExplainedService, names []string,
+) ([]compose.ExplainedService, error) {
+	if len(names) == 0 {
+		return explained, nil
+	}
+
+	byName := map[string]compose.ExplainedService{}
+	for _, svc := range explained {
+		byName[svc.Name] = svc
+	}
+
+	selected := make([]compose.ExplainedService, 0, len(names))
+	for _, name := range names {
+		svc, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("no such service: %q", name)
+		}
+		selected = append(selected, svc)
+	}
+
+	return selected, nil
+}
+
+// printExplanation prints each service's fields in columns. Fields set by docket's generated
+// files are marked (and colored, on a terminal).
+func printExplanation(w io.Writer, explained []compose.ExplainedService) {
+	generated := color.New(color.FgYellow).SprintFunc()
+
+	for i, svc := range explained {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s:\n", svc.Name)
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, f := range svc.Fields {
+			file := f.File
+			if f.Generated {
+				file = generated(file + " (generated)")
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", f.Field, formatExplainedValue(f.Value), file)
+		}
+		tw.Flush()
+	}
+}
+
+func printExplanationJSON(w io.Writer, explained []compose.ExplainedService) error {
+	out := []explainedFieldJSON{}
+	for _, svc := range explained {
+		for _, f := range svc.Fields {
+			out = append(out, explainedFieldJSON{
+				Service:   svc.Name,
+				Field:     f.Field,
+				Value:     f.Value,
+				File:      f.File,
+				Generated: f.Generated,
+			})
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(out)
+}
+
+// formatExplainedValue shows strings as they are and everything else as compact JSON.
+func formatExplainedValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(data)
+}
@@ -0,0 +1,453 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/bloomberg/docket/internal/compose"
+)
+
+// initFile is a file that dkt init writes. Its name and contents are templates.
+type initFile struct {
+	name     string
+	contents string
+}
+
+type initTemplate struct {
+	description string
+	mode        string // the mode to suggest running
+	files       []initFile
+}
+
+// initData is what the initFile templates can use.
+type initData struct {
+	Prefix  string
+	Package string
+	GoImage string
+}
+
+// testerService is the service that runs `go test`. `sleep infinity` keeps the container
+// running, and `init: true` lets it stop quickly.
+const testerService = `  tester:
+    image: {{ .GoImage }}
+    command: ["sleep", "infinity"]
+    init: true
+    labels:
+      com.bloomberg.docket: "run go test"
+`
+
+const initTestHeader = `package {{ .Package }}
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bloomberg/docket"
+)
+
+// addr returns the address of a service. Inside the tester service, the mode's files set
+// envVar. On the host, set DOCKET_PORT_ENV=1 so that docket sets portEnvVar.
+func addr(t *testing.T, envVar, portEnvVar string) string {
+	if a := os.Getenv(envVar); a != "" {
+		return a
+	}
+	if a := os.Getenv(portEnvVar); a != "" {
+		return a
+	}
+
+	t.Fatalf("set %s or DOCKET_PORT_ENV=1", envVar)
+
+	return ""
+}
+
+// dial connects to addr, retrying while the service starts.
+func dial(t *testing.T, addr string) net.Conn {
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err == nil {
+			return conn
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("failed to connect to %s: %v", addr, err)
+		}
+		time.Sleep(time.Second)
+	}
+}
+`
+
+var initTemplates = map[string]initTemplate{
+	"hello": {
+		description: "a test service with an environment variable",
+		mode:        "full",
+		files: []initFile{
+			{name: "{{ .Prefix }}.yaml", contents: `version: "3.7"
+
+services:
+` + testerService + `    environment:
+      HELLO: world
+`},
+			{name: "{{ .Prefix }}.full.yaml", contents: `version: "3.7"
+# The full mode uses {{ .Prefix }}.yaml as is.
+`},
+			{name: "docket_test.go", contents: `package {{ .Package }}
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/bloomberg/docket"
+)
+
+func TestHello(t *testing.T) {
+	docket.Run(context.Background(), nil, t, func() {
+		if hello := os.Getenv("HELLO"); hello != "world" {
+			t.Errorf("HELLO had value %q", hello)
+		}
+	})
+}
+`},
+		},
+	},
+
+	"redis": {
+		description: "a test that talks to Redis",
+		mode:        "full",
+		files: []initFile{
+			{name: "{{ .Prefix }}.yaml", contents: `version: "3.7"
+
+services:
+  redis:
+    image: redis:6
+`},
+			{name: "{{ .Prefix }}.full.yaml", contents: `version: "3.7"
+
+services:
+` + testerService + `    environment:
+      REDIS_ADDR: redis:6379
+`},
+			{name: "{{ .Prefix }}.debug.yaml", contents: `version: "3.7"
+
+# Run the tests on the host with DOCKET_MODE=debug DOCKET_PORT_ENV=1.
+services:
+  redis:
+    ports:
+      - "6379"
+`},
+			{name: "docket_test.go", contents: initTestHeader + `
+func TestRedis(t *testing.T) {
+	docket.Run(context.Background(), nil, t, func() {
+		conn := dial(t, addr(t, "REDIS_ADDR", "DOCKET_REDIS_6379_ADDR"))
+		defer conn.Close()
+
+		if _, err := conn.Write([]byte("PING\r\n")); err != nil {
+			t.Fatal(err)
+		}
+
+		reply := make([]byte, 64)
+		n, err := conn.Read(reply)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(reply[:n]) != "+PONG\r\n" {
+			t.Errorf("unexpected reply %q", reply[:n])
+		}
+	})
+}
+`},
+		},
+	},
+
+	"postgres": {
+		description: "a test that talks to PostgreSQL",
+		mode:        "full",
+		files: []initFile{
+			{name: "{{ .Prefix }}.yaml", contents: `version: "3.7"
+
+services:
+  postgres:
+    image: postgres:13
+    environment:
+      POSTGRES_PASSWORD: docket
+`},
+			{name: "{{ .Prefix }}.full.yaml", contents: `version: "3.7"
+
+services:
+` + testerService + `    environment:
+      POSTGRES_ADDR: postgres:5432
+`},
+			{name: "{{ .Prefix }}.debug.yaml", contents: `version: "3.7"
+
+# Run the tests on the host with DOCKET_MODE=debug DOCKET_PORT_ENV=1.
+services:
+  postgres:
+    ports:
+      - "5432"
+`},
+			{name: "docket_test.go", contents: initTestHeader + `
+func TestPostgres(t *testing.T) {
+	docket.Run(context.Background(), nil, t, func() {
+		// Replace this with database/sql and your favorite driver. The password is "docket".
+		conn := dial(t, addr(t, "POSTGRES_ADDR", "DOCKET_POSTGRES_5432_ADDR"))
+		conn.Close()
+	})
+}
+`},
+		},
+	},
+
+	"service-under-test": {
+		description: "a test that sends HTTP requests to this package's main program",
+		mode:        "full",
+		files: []initFile{
+			{name: "{{ .Prefix }}.yaml", contents: `version: "3.7"
+
+services:
+  service:
+    image: {{ .GoImage }}
+    # The program should listen on the address in its first argument.
+    command: ["go", "run", ".", ":8080"]
+    labels:
+      com.bloomberg.docket: "mount go sources"
+`},
+			{name: "{{ .Prefix }}.full.yaml", contents: `version: "3.7"
+
+services:
+` + testerService + `    environment:
+      SERVICE_ADDR: service:8080
+`},
+			{name: "{{ .Prefix }}.debug.yaml", contents: `version: "3.7"
+
+# Run the tests on the host with DOCKET_MODE=debug DOCKET_PORT_ENV=1.
+services:
+  service:
+    ports:
+      - "8080"
+`},
+			{name: "docket_test.go", contents: initTestHeader + `
+func TestService(t *testing.T) {
+	docket.Run(context.Background(), nil, t, func() {
+		conn := dial(t, addr(t, "SERVICE_ADDR", "DOCKET_SERVICE_8080_ADDR"))
+		defer conn.Close()
+
+		// Replace this with requests to your service.
+		if _, err := conn.Write([]byte("GET / HTTP/1.0\r\n\r\n")); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+`},
+		},
+	},
+}
+
+// runInit writes docket files and a sample test from a template.
+func runInit(stdout, stderr io.Writer, opts options, args []string) int {
+	force := false
+	var names []string
+	for _, arg := range args {
+		switch {
+		case arg == "--force" || arg == "-f":
+			force = true
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintf(stderr, "ERROR: unknown argument to init: %q\n", arg)
+
+			return 1
+		default:
+			names = append(names, arg)
+		}
+	}
+
+	if len(names) != 1 {
+		fmt.Fprintf(stderr, "ERROR: usage: dkt init [--force] TEMPLATE\n\nTemplates:\n")
+		printInitTemplates(stderr)
+
+		return 1
+	}
+
+	tmpl, ok := initTemplates[names[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "ERROR: unknown template %q\n\nTemplates:\n", names[0])
+		printInitTemplates(stderr)
+
+		return 1
+	}
+
+	data, err := makeInitData(context.Background(), opts.Prefix)
+	if err != nil {
+		fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+		return 1
+	}
+
+	files, err := renderInitFiles(tmpl, data)
+	if err != nil {
+		fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+		return 1
+	}
+
+	if !force {
+		var existing []string
+		for _, f := range files {
+			if _, err := os.Stat(f.name); err == nil {
+				existing = append(existing, f.name)
+			}
+		}
+		if len(existing) > 0 {
+			fmt.Fprintf(stderr, "ERROR: not overwriting %s (use --force)\n",
+				strings.Join(existing, ", "))
+
+			return 1
+		}
+	}
+
+	for _, f := range files {
+		contents := []byte(f.contents)
+		if err := ioutil.WriteFile(f.name, contents, 0644); err != nil { //nolint:gosec // checked in
+			fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+			return 1
+		}
+		fmt.Fprintf(stdout, "wrote %s\n", f.name)
+	}
+
+	fmt.Fprintf(stdout, "\nTo run the tests with docket:\n\n  DOCKET_MODE=%s go test -v\n",
+		tmpl.mode)
+
+	return 0
+}
+
+func printInitTemplates(w io.Writer) {
+	names := make([]string, 0, len(initTemplates))
+	for name := range initTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-20s %s\n", name, initTemplates[name].description)
+	}
+}
+
+func makeInitData(ctx context.Context, prefix string) (initData, error) {
+	goVersion, err := compose.GoDirective(ctx)
+	if err != nil {
+		return initData{}, err
+	}
+
+	pkg, err := findPackageName(".")
+	if err != nil {
+		return initData{}, err
+	}
+
+	return initData{Prefix: prefix, Package: pkg, GoImage: goImage(goVersion)}, nil
+}
+
+// goImage returns the golang image for a go directive's version.
+func goImage(goVersion string) string {
+	if goVersion == "" {
+		return "golang:1"
+	}
+
+	return "golang:" + goVersion
+}
+
+var nonIdentifierChars = regexp.MustCompile(`[^a-z0-9_]`)
+
+// findPackageName returns the name of the package in dir, or a name based on dir's name if it
+// doesn't have any Go files yet.
+func findPackageName(dir string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", fmt.Errorf("failed to find Go files: %w", err)
+	}
+
+	for _, m := range matches {
+		if strings.HasSuffix(m, "_test.go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(token.NewFileSet(), m, nil, parser.PackageClauseOnly)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", m, err)
+		}
+
+		return f.Name.Name, nil
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to find package name: %w", err)
+	}
+
+	name := nonIdentifierChars.ReplaceAllString(strings.ToLower(filepath.Base(abs)), "")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "pkg" + name
+	}
+
+	return name, nil
+}
+
+func renderInitFiles(tmpl initTemplate, data initData) ([]initFile, error) {
+	files := make([]initFile, len(tmpl.files))
+
+	for i, f := range tmpl.files {
+		name, err := renderInitTemplate(f.name, data)
+		if err != nil {
+			return nil, err
+		}
+
+		contents, err := renderInitTemplate(f.contents, data)
+		if err != nil {
+			return nil, err
+		}
+
+		files[i] = initFile{name: name, contents: contents}
+	}
+
+	return files, nil
+}
+
+func renderInitTemplate(text string, data initData) (string, error) {
+	t, err := template.New("").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return b.String(), nil
+}
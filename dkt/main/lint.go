@@ -0,0 +1,109 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bloomberg/docket/internal/compose"
+)
+
+type lintProblemJSON struct {
+	Severity string   `json:"severity"`
+	Modes    []string `json:"modes"`
+	File     string   `json:"file"`
+	Service  string   `json:"service,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// runLint checks the docket files for every mode and exits non-zero if it finds errors.
+func runLint(stdout, stderr io.Writer, opts options, args []string) int {
+	asJSON := false
+	for _, arg := range args {
+		switch arg {
+		case "--json":
+			asJSON = true
+		default:
+			fmt.Fprintf(stderr, "ERROR: unknown argument to lint: %q\n", arg)
+
+			return 1
+		}
+	}
+
+	problems, err := compose.Lint(opts.Prefix)
+	if err != nil {
+		fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+		return 1
+	}
+
+	if asJSON {
+		out := make([]lintProblemJSON, len(problems))
+		for i, p := range problems {
+			modes := p.Modes
+			if modes == nil {
+				modes = []string{}
+			}
+			out[i] = lintProblemJSON{
+				Severity: p.Severity,
+				Modes:    modes,
+				File:     p.File,
+				Service:  p.Service,
+				Message:  p.Message,
+			}
+		}
+
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+			return 1
+		}
+	} else {
+		for _, p := range problems {
+			fmt.Fprintln(stdout, formatLintProblem(p))
+		}
+	}
+
+	for _, p := range problems {
+		if p.Severity == compose.LintError {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// formatLintProblem formats a problem like "FILE: SERVICE: SEVERITY: MESSAGE (modes: MODES)".
+func formatLintProblem(p compose.LintProblem) string {
+	var b strings.Builder
+
+	if p.File != "" {
+		fmt.Fprintf(&b, "%s: ", p.File)
+	}
+	if p.Service != "" {
+		fmt.Fprintf(&b, "%s: ", p.Service)
+	}
+	fmt.Fprintf(&b, "%s: %s", p.Severity, p.Message)
+	if len(p.Modes) > 0 {
+		fmt.Fprintf(&b, " (modes: %s)", strings.Join(p.Modes, ", "))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/bloomberg/docket/internal/compose"
+)
+
+// runLock resolves the images of every mode to digests and writes them to the lock file. With
+// --check, it reports the differences between the lock file and the docket files instead. (The
+// check only compares which images are locked, not their digests.)
+func runLock(stdout, stderr io.Writer, opts options, args []string) int {
+	check := false
+	for _, arg := range args {
+		switch arg {
+		case "--check":
+			check = true
+		default:
+			fmt.Fprintf(stderr, "ERROR: unknown argument to lock: %q\n", arg)
+
+			return 1
+		}
+	}
+
+	ctx := context.Background()
+
+	images, err := compose.ModeImages(ctx, opts.Prefix)
+	if err != nil {
+		fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+		return 1
+	}
+
+	lockFile := compose.LockFile(opts.Prefix)
+
+	if check {
+		return checkLock(stdout, stderr, lockFile, images)
+	}
+
+	lock := compose.ImageLock{Images: map[string]string{}}
+
+	for _, image := range sortedImages(images) {
+		pinned, err := compose.ResolveImageDigest(ctx, image)
+		if err != nil {
+			fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+			return 1
+		}
+
+		lock.Images[image] = pinned
+		fmt.Fprintf(stdout, "%s -> %s\n", image, pinned)
+	}
+
+	if err := compose.WriteImageLock(lockFile, lock); err != nil {
+		fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "wrote %s\n", lockFile)
+
+	return 0
+}
+
+// checkLock prints the differences between the lock file and images and returns 1 if there
+// are any.
+func checkLock(stdout, stderr io.Writer, lockFile string, images map[string][]string) int {
+	lock, err := compose.ReadImageLock(lockFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+		return 1
+	}
+	if lock == nil {
+		fmt.Fprintf(stderr, "ERROR: %s doesn't exist (run dkt lock)\n", lockFile)
+
+		return 1
+	}
+
+	drift := compose.CheckImageLock(*lock, images)
+	for _, d := range drift {
+		fmt.Fprintln(stdout, formatLockDrift(lockFile, d))
+	}
+
+	if len(drift) > 0 {
+		fmt.Fprintf(stderr, "ERROR: %s is out of date (run dkt lock)\n", lockFile)
+
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "%s is up to date\n", lockFile)
+
+	return 0
+}
+
+func formatLockDrift(lockFile string, d compose.LockDrift) string {
+	s := fmt.Sprintf("%s: %s: %s", lockFile, d.Image, d.Message)
+	if len(d.Modes) > 0 {
+		s += fmt.Sprintf(" (modes: %s)", strings.Join(d.Modes, ", "))
+	}
+
+	return s
+}
+
+func sortedImages(images map[string][]string) []string {
+	sorted := make([]string, 0, len(images))
+	for image := range images {
+		sorted = append(sorted, image)
+	}
+	sort.Strings(sorted)
+
+	return sorted
+}
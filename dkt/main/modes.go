@@ -0,0 +1,76 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/bloomberg/docket/internal/compose"
+)
+
+type modeJSON struct {
+	Mode  string   `json:"mode"`
+	Files []string `json:"files"`
+}
+
+// runModes lists the modes in the current directory and the files each one uses.
+func runModes(stdout, stderr io.Writer, opts options, args []string) int {
+	asJSON := false
+	for _, arg := range args {
+		switch arg {
+		case "--json":
+			asJSON = true
+		default:
+			fmt.Fprintf(stderr, "ERROR: unknown argument to modes: %q\n", arg)
+
+			return 1
+		}
+	}
+
+	modes, err := compose.FindModes(opts.Prefix)
+	if err != nil {
+		fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+		return 1
+	}
+
+	if asJSON {
+		out := make([]modeJSON, len(modes))
+		for i, m := range modes {
+			out[i] = modeJSON{Mode: m.Name, Files: m.Files}
+		}
+
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+			return 1
+		}
+
+		return 0
+	}
+
+	for _, m := range modes {
+		fmt.Fprintf(stdout, "%s\n", m.Name)
+		for _, f := range m.Files {
+			fmt.Fprintf(stdout, "  %s\n", f)
+		}
+	}
+
+	return 0
+}
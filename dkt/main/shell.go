@@ -0,0 +1,81 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bloomberg/docket/internal/compose"
+)
+
+// shellScript starts bash if the image has it and sh otherwise. The working directory is passed
+// as $1 so that it doesn't need quoting.
+const shellScript = `cd "$1" || exit; if command -v bash >/dev/null 2>&1; then exec bash; fi; exec sh`
+
+// runShell brings up the app and opens an interactive shell inside a service.
+func runShell(stdin io.Reader, stdout, stderr io.Writer, opts options, args []string) int {
+	if len(args) > 1 {
+		fmt.Fprintf(stderr, "ERROR: too many arguments to shell: %q\n", args)
+
+		return 1
+	}
+
+	return withCompose(stderr, opts, func(ctx context.Context, cmp *compose.Compose) int {
+		service := cmp.TestService()
+		if len(args) == 1 {
+			service = args[0]
+		}
+		if service == "" {
+			fmt.Fprintf(stderr, "ERROR: mode %q has no test service, so name a service\n", cmp.Mode())
+
+			return 1
+		}
+
+		workingDir, err := cmp.WorkingDir(ctx, service)
+		if err != nil {
+			fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+			return 1
+		}
+
+		printShellInfo(stderr, cmp, service, workingDir)
+
+		if err := cmp.Up(ctx); err != nil {
+			fmt.Fprintf(stderr, "ERROR: failed to bring up the app: %v\n", err)
+
+			return 1
+		}
+
+		if workingDir == "" {
+			workingDir = "."
+		}
+
+		return runPassthrough(stdin, stdout, stderr,
+			cmp.Command(ctx, "exec", service, "sh", "-c", shellScript, "sh", workingDir))
+	})
+}
+
+func printShellInfo(w io.Writer, cmp *compose.Compose, service, workingDir string) {
+	fmt.Fprintf(w, "mode:        %s\n", cmp.Mode())
+	fmt.Fprintf(w, "project:     %s\n", cmp.ProjectName())
+	fmt.Fprintf(w, "files:       %s\n", strings.Join(cmp.Files(), " "))
+	fmt.Fprintf(w, "service:     %s\n", service)
+	if workingDir != "" {
+		fmt.Fprintf(w, "working dir: %s\n", workingDir)
+	}
+}
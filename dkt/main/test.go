@@ -0,0 +1,181 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/bloomberg/docket/internal/compose"
+)
+
+// runTest brings up the app and runs `go test` inside the test service the way docket does.
+func runTest(stdin io.Reader, stdout, stderr io.Writer, opts options, args []string) int {
+	printOnly, runArg, testArgs, err := parseTestArgs(args)
+	if err != nil {
+		fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+		return 1
+	}
+
+	return withCompose(stderr, opts, func(ctx context.Context, cmp *compose.Compose) int {
+		if cmp.TestService() == "" {
+			fmt.Fprintf(stderr, "ERROR: mode %q has no test service\n", cmp.Mode())
+
+			return 1
+		}
+
+		if printOnly {
+			if err := printTestCommands(ctx, stdout, cmp, runArg, testArgs); err != nil {
+				fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+				return 1
+			}
+
+			return 0
+		}
+
+		if err := cmp.Up(ctx); err != nil {
+			fmt.Fprintf(stderr, "ERROR: failed to bring up the app: %v\n", err)
+
+			return 1
+		}
+
+		cmd, err := testCommand(ctx, cmp, runArg, testArgs)
+		if err != nil {
+			fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+			return 1
+		}
+
+		return runPassthrough(stdin, stdout, stderr, cmd)
+	})
+}
+
+// testCommand builds the test binary (if the test service uses one) and returns the command
+// that runs the tests inside the test service.
+func testCommand(
+	ctx context.Context, cmp *compose.Compose, runArg string, testArgs []string,
+) (*exec.Cmd, error) {
+	var binaryPath string
+	if cmp.UsesTestBinary() {
+		var err error
+		if binaryPath, err = cmp.BuildTestBinary(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return cmp.ExecGoTestCommand(ctx, binaryPath, runArg, testArgs), nil
+}
+
+// parseTestArgs separates dkt test's --print option and go test's -run argument from the rest
+// of the arguments.
+//
+// A -run argument that names a test (e.g., TestFoo/bar) is translated the same way docket
+// translates the name of the test that called docket.Run, so that `dkt test -run TestFoo`
+// runs the same command that docket would. Regular expressions are passed through unchanged.
+func parseTestArgs(
+	args []string,
+) (printOnly bool, runArg string, testArgs []string, err error) {
+	testArgs = []string{}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "-args" || arg == "--args" || arg == "--":
+			return printOnly, runArg, append(testArgs, args[i:]...), nil
+
+		case arg == "--print":
+			printOnly = true
+
+		case arg == "-run" || arg == "--run":
+			if i+1 >= len(args) {
+				return false, "", nil, missingParamForOptionError(arg)
+			}
+			i++
+			runArg = translateRunArg(args[i])
+
+		case strings.HasPrefix(arg, "-run=") || strings.HasPrefix(arg, "--run="):
+			runArg = translateRunArg(arg[strings.Index(arg, "=")+1:])
+
+		default:
+			testArgs = append(testArgs, arg)
+		}
+	}
+
+	return printOnly, runArg, testArgs, nil
+}
+
+func translateRunArg(runArg string) string {
+	if runArg == "" {
+		return ""
+	}
+
+	for _, part := range strings.Split(runArg, "/") {
+		if part == "" || regexp.QuoteMeta(part) != part {
+			return runArg
+		}
+	}
+
+	return compose.RunArgForTest(runArg, "")
+}
+
+// printTestCommands prints the commands that runTest would run. They use a saved copy of the
+// app's config, since the files docket generates for this run are removed when dkt exits.
+func printTestCommands(
+	ctx context.Context, w io.Writer, cmp *compose.Compose, runArg string, testArgs []string,
+) error {
+	cmp, err := cmp.SaveConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, shellJoin(cmp.Command(ctx, "up", "-d").Args))
+
+	binaryPath := ""
+	if cmp.UsesTestBinary() {
+		var build string
+		if build, binaryPath, err = cmp.DescribeTestBinaryBuild(ctx); err != nil {
+			return err
+		}
+		fmt.Fprintln(w, build)
+	}
+
+	fmt.Fprintln(w, shellJoin(cmp.ExecGoTestCommand(ctx, binaryPath, runArg, testArgs).Args))
+
+	return nil
+}
+
+var shellSafe = regexp.MustCompile(`^[-A-Za-z0-9_./=:,@%+]+$`)
+
+// shellJoin joins args into a command line that a POSIX shell would split back into args.
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+
+	for i, arg := range args {
+		if shellSafe.MatchString(arg) {
+			quoted[i] = arg
+		} else {
+			quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+	}
+
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,277 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bloomberg/docket/internal/compose"
+)
+
+// watchInterval is how often dkt watch looks for changed files.
+var watchInterval = time.Second
+
+// runWatch brings up the app, runs the tests, and runs them again whenever the package's Go
+// files or the docket files change. It leaves the app up when you stop it with Ctrl-C.
+func runWatch(stdout, stderr io.Writer, opts options, args []string) int {
+	opts = withDefaultPrefix(opts)
+	if opts.Mode == "" {
+		fmt.Fprintf(stderr, "ERROR: use -m|--mode or set $DOCKET_MODE\n")
+
+		return 1
+	}
+
+	_, runArg, testArgs, err := parseTestArgs(args)
+	if err != nil {
+		fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+		return 1
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ctx := context.Background()
+	w := &watcher{
+		stdout:   stdout,
+		stderr:   stderr,
+		prefix:   opts.Prefix,
+		mode:     opts.Mode,
+		runArg:   runArg,
+		testArgs: testArgs,
+		cmp:      nil,
+		cleanup:  func() error { return nil },
+	}
+	defer w.close()
+
+	snap, err := snapshotWatchedFiles(opts.Prefix)
+	if err != nil {
+		fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+		return 1
+	}
+
+	exitCode := w.reload(ctx)
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-interrupt:
+			fmt.Fprintf(stderr,
+				"dkt watch: stopping (the app is still up; use `dkt down` to stop it)\n")
+
+			return exitCode
+
+		case <-ticker.C:
+			newSnap, err := snapshotWatchedFiles(opts.Prefix)
+			if err != nil {
+				fmt.Fprintf(stderr, "ERROR: %v\n", err)
+
+				continue
+			}
+
+			changes := diffSnapshots(snap, newSnap)
+			snap = newSnap
+
+			switch {
+			case len(changes.docketFiles) > 0:
+				fmt.Fprintf(stderr, "dkt watch: %s changed\n",
+					strings.Join(changes.docketFiles, ", "))
+				exitCode = w.reload(ctx)
+
+			case len(changes.goFiles) > 0:
+				fmt.Fprintf(stderr, "dkt watch: %s changed\n", strings.Join(changes.goFiles, ", "))
+				exitCode = w.restart(ctx)
+			}
+		}
+	}
+}
+
+type watcher struct {
+	stdout, stderr io.Writer
+
+	prefix, mode string
+	runArg       string
+	testArgs     []string
+	cmp          *compose.Compose
+	cleanup      func() error
+}
+
+// reload sets up docket again (since the docket files changed), brings up the app, which
+// recreates any services whose configuration changed, and runs the tests.
+func (w *watcher) reload(ctx context.Context) int {
+	w.close()
+
+	cmp, cleanup, err := compose.NewCompose(ctx, w.prefix, w.mode)
+	if err != nil {
+		fmt.Fprintf(w.stderr, "ERROR: %v\n", err)
+
+		return 1
+	}
+	w.cmp, w.cleanup = cmp, cleanup
+
+	if cmp.TestService() == "" {
+		fmt.Fprintf(w.stderr, "ERROR: mode %q has no test service\n", cmp.Mode())
+
+		return 1
+	}
+
+	if err := cmp.Up(ctx); err != nil {
+		fmt.Fprintf(w.stderr, "ERROR: failed to bring up the app: %v\n", err)
+
+		return 1
+	}
+
+	return w.runTests(ctx)
+}
+
+// restart restarts the services that mount Go sources (since the sources changed) and runs
+// the tests.
+func (w *watcher) restart(ctx context.Context) int {
+	if w.cmp == nil {
+		return w.reload(ctx)
+	}
+
+	if services := w.cmp.SourceMountServices(); len(services) > 0 {
+		cmd := w.cmp.Command(ctx, append([]string{"restart"}, services...)...)
+		if exitCode := runWatchedCommand(w.stdout, w.stderr, cmd); exitCode != 0 {
+			return exitCode
+		}
+	}
+
+	return w.runTests(ctx)
+}
+
+func (w *watcher) runTests(ctx context.Context) int {
+	cmd, err := testCommand(ctx, w.cmp, w.runArg, w.testArgs)
+	if err != nil {
+		fmt.Fprintf(w.stderr, "ERROR: %v\n", err)
+
+		return 1
+	}
+
+	exitCode := runWatchedCommand(w.stdout, w.stderr, cmd)
+	fmt.Fprintf(w.stderr,
+		"dkt watch: tests exited with %d; watching for changes (Ctrl-C to stop)\n", exitCode)
+
+	return exitCode
+}
+
+func (w *watcher) close() {
+	if err := w.cleanup(); err != nil {
+		fmt.Fprintf(w.stderr, "ERROR cleaning up: %v\n", err)
+	}
+	w.cmp, w.cleanup = nil, func() error { return nil }
+}
+
+// runWatchedCommand runs cmd and returns its exit code. Unlike runPassthrough, it doesn't
+// ignore interrupts, since dkt watch stops when you press Ctrl-C.
+func runWatchedCommand(stdout, stderr io.Writer, cmd *exec.Cmd) int {
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+
+		fmt.Fprintf(stderr, "ERROR: failed to run %v: %v\n", cmd.Args, err)
+
+		return 1
+	}
+
+	return 0
+}
+
+// watchSnapshot has the modification times of the files dkt watch watches.
+type watchSnapshot map[string]time.Time
+
+type watchChanges struct {
+	goFiles     []string
+	docketFiles []string
+}
+
+// snapshotWatchedFiles finds the Go files and the docket files in the current directory.
+// (Docket's generated files are in its state directory, so they aren't watched.)
+func snapshotWatchedFiles(prefix string) (watchSnapshot, error) {
+	infos, err := ioutil.ReadDir(".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read current dir: %w", err)
+	}
+
+	snap := watchSnapshot{}
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() {
+			continue
+		}
+
+		if filepath.Ext(name) == ".go" || isDocketFile(prefix, name) {
+			snap[name] = info.ModTime()
+		}
+	}
+
+	return snap, nil
+}
+
+func isDocketFile(prefix, name string) bool {
+	ext := filepath.Ext(name)
+
+	return strings.HasPrefix(name, prefix+".") && (ext == ".yaml" || ext == ".yml")
+}
+
+// diffSnapshots finds the files that were added, removed, or modified between two snapshots.
+func diffSnapshots(before, after watchSnapshot) watchChanges {
+	var changes watchChanges
+
+	addChange := func(name string) {
+		if filepath.Ext(name) == ".go" {
+			changes.goFiles = append(changes.goFiles, name)
+		} else {
+			changes.docketFiles = append(changes.docketFiles, name)
+		}
+	}
+
+	for name, mtime := range after {
+		if old, ok := before[name]; !ok || !old.Equal(mtime) {
+			addChange(name)
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			addChange(name)
+		}
+	}
+
+	sort.Strings(changes.goFiles)
+	sort.Strings(changes.docketFiles)
+
+	return changes
+}
@@ -21,11 +21,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"os/signal"
 	"runtime"
 	"runtime/debug"
+	"strings"
 
 	"github.com/bloomberg/docket/internal/tempbuild"
 )
@@ -47,6 +49,15 @@ func run(
 	debugTraceEnabled, keepExecutable bool,
 	stdin io.Reader, stdout, stderr io.Writer, args ...string,
 ) int {
+	runnerOpts, args := parseRunnerArgs(args)
+	if runnerOpts.dir != "" {
+		if err := os.Chdir(runnerOpts.dir); err != nil {
+			fmt.Fprintf(stderr, "ERROR: failed to change dir: %v\n", err)
+
+			return 1
+		}
+	}
+
 	if debugTraceEnabled {
 		printRunnerBuildInfo(debugPrefix, stderr)
 
@@ -68,10 +79,8 @@ func run(
 		printRunnerBuildInfo("", stdout)
 	}
 
-	if debugTraceEnabled {
-		fmt.Fprintf(stderr, "%sbuilding %s\n", debugPrefix, actualMainPkg)
-	}
-	dktExePath, err := tempbuild.Build(context.Background(), actualMainPkg, "dkt.")
+	dktExePath, cleanup, err := buildDkt(
+		debugTraceEnabled, keepExecutable, runnerOpts.rebuild, stderr)
 	if err != nil {
 		fmt.Fprintf(stderr, "ERROR: failed to build dkt: %v\n", err)
 		fmt.Fprintf(stderr, "--- diagnostics follow ---\n")
@@ -79,9 +88,7 @@ func run(
 
 		return 1
 	}
-	if !keepExecutable {
-		defer os.Remove(dktExePath)
-	}
+	defer cleanup()
 
 	if debugTraceEnabled {
 		fmt.Fprintf(stderr, "%s%v\n", debugPrefix, append([]string{dktExePath}, args...))
@@ -90,6 +97,101 @@ func run(
 	return runDkt(stdin, stdout, stderr, dktExePath, args)
 }
 
+// runnerOptions are the options that the runner handles instead of dkt.
+type runnerOptions struct {
+	rebuild bool
+	dir     string
+}
+
+// parseRunnerArgs removes the runner's own options from dkt's options, which come before
+// the first argument that isn't an option.
+//
+// The runner builds dkt in the directory from -C, so it changes to that directory and
+// removes -C too.
+func parseRunnerArgs(args []string) (runnerOptions, []string) {
+	var opts runnerOptions
+	dktArgs := make([]string, 0, len(args))
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--rebuild":
+			opts.rebuild = true
+
+		case arg == "-C" || arg == "--directory":
+			if i+1 < len(args) {
+				i++
+				opts.dir = args[i]
+			} else {
+				dktArgs = append(dktArgs, arg) // let dkt complain
+			}
+		case strings.HasPrefix(arg, "--directory="):
+			opts.dir = arg[len("--directory="):]
+		case strings.HasPrefix(arg, "-C"):
+			opts.dir = arg[len("-C"):]
+
+		case arg == "-m" || arg == "--mode" || arg == "-P" || arg == "--prefix" || arg == "--all":
+			dktArgs = append(dktArgs, arg)
+			if i+1 < len(args) {
+				i++
+				dktArgs = append(dktArgs, args[i])
+			}
+
+		case !strings.HasPrefix(arg, "-"):
+			return opts, append(dktArgs, args[i:]...)
+
+		default:
+			dktArgs = append(dktArgs, arg)
+		}
+	}
+
+	return opts, dktArgs
+}
+
+// buildDkt returns the path of a dkt executable and a function that cleans it up.
+//
+// Normally, dkt is cached in the user's cache directory and only rebuilt when it's stale or
+// rebuild is true. If there's no cache directory, dkt is built in a temporary file, which
+// cleanup removes unless keepExecutable is true.
+func buildDkt(
+	debugTraceEnabled, keepExecutable, rebuild bool, stderr io.Writer,
+) (string, func(), error) {
+	ctx := context.Background()
+	noop := func() {}
+
+	trace := ioutil.Discard
+	if debugTraceEnabled {
+		trace = stderr
+	}
+
+	cache, err := newDktCache()
+	if err == nil {
+		mainPkg, err := goListActualMainPkg(debugPrefix, trace)
+		if err != nil {
+			return "", noop, err
+		}
+
+		path, err := buildCachedDkt(ctx, cache, mainPkg, rebuild, trace)
+
+		return path, noop, err
+	}
+
+	fmt.Fprintf(trace, "%snot caching dkt: %v\n", debugPrefix, err)
+	fmt.Fprintf(trace, "%sbuilding %s\n", debugPrefix, actualMainPkg)
+
+	path, err := tempbuild.Build(ctx, actualMainPkg, "dkt.")
+	if err != nil {
+		return "", noop, err
+	}
+
+	if keepExecutable {
+		return path, noop, nil
+	}
+
+	return path, func() { os.Remove(path) }, nil
+}
+
 func runDkt(stdin io.Reader, stdout, stderr io.Writer, dktExePath string, args []string) int {
 	cmd := exec.Command(dktExePath, args...)
 
@@ -15,9 +15,14 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/bloomberg/go-testgroup"
 )
@@ -77,3 +82,205 @@ func (grp *dktRunnerTests) DebugTrace(t *testgroup.T) {
 	t.Regexp(debugPrefix+"(current module|not in module-aware mode)", stderr.String())
 	t.Regexp(debugPrefix+"(found dkt module|found dkt inside the GOPATH)", stderr.String())
 }
+
+func (grp *dktRunnerTests) ParseRunnerArgs(t *testgroup.T) {
+	testCases := []struct {
+		args     []string
+		wantOpts runnerOptions
+		wantArgs []string
+	}{
+		{[]string{}, runnerOptions{rebuild: false, dir: ""}, []string{}},
+		{[]string{"--rebuild"}, runnerOptions{rebuild: true, dir: ""}, []string{}},
+		{
+			[]string{"--rebuild", "-m", "full", "up"},
+			runnerOptions{rebuild: true, dir: ""},
+			[]string{"-m", "full", "up"},
+		},
+		{
+			[]string{"-m", "full", "--rebuild", "ps"},
+			runnerOptions{rebuild: true, dir: ""},
+			[]string{"-m", "full", "ps"},
+		},
+		{
+			[]string{"--mode", "--rebuild", "ps"},
+			runnerOptions{rebuild: false, dir: ""},
+			[]string{"--mode", "--rebuild", "ps"},
+		},
+		{
+			[]string{"exec", "svc", "--rebuild"},
+			runnerOptions{rebuild: false, dir: ""},
+			[]string{"exec", "svc", "--rebuild"},
+		},
+		{
+			[]string{"-C", "pkg", "-mfull", "ps"},
+			runnerOptions{rebuild: false, dir: "pkg"},
+			[]string{"-mfull", "ps"},
+		},
+		{
+			[]string{"-Cpkg", "--all", "./...", "ps"},
+			runnerOptions{rebuild: false, dir: "pkg"},
+			[]string{"--all", "./...", "ps"},
+		},
+		{
+			[]string{"--directory=pkg", "ps"},
+			runnerOptions{rebuild: false, dir: "pkg"},
+			[]string{"ps"},
+		},
+		{[]string{"-C"}, runnerOptions{rebuild: false, dir: ""}, []string{"-C"}},
+	}
+
+	for _, tc := range testCases {
+		opts, args := parseRunnerArgs(tc.args)
+		t.Equal(tc.wantOpts, opts, tc.args)
+		t.Equal(tc.wantArgs, args, tc.args)
+	}
+}
+
+func (grp *dktRunnerTests) CacheKey(t *testgroup.T) {
+	in := cacheKeyInputs{
+		ModulePath:    "github.com/bloomberg/docket",
+		ModuleVersion: "v1.0.0",
+		GoSum:         "abc",
+		GoVersion:     "go version go1.15 linux/amd64",
+		GoFlags:       "",
+		GOOS:          "linux",
+		GOARCH:        "amd64",
+		SourceHash:    "",
+	}
+	t.Len(in.key(), 32)
+	t.Equal(in.key(), in.key())
+
+	changed := in
+	changed.GoVersion = "go version go1.16 linux/amd64"
+	t.NotEqual(in.key(), changed.key())
+
+	changed = in
+	changed.GoSum = "def"
+	t.NotEqual(in.key(), changed.key())
+
+	changed = in
+	changed.GoFlags = "-tags=integration"
+	t.NotEqual(in.key(), changed.key())
+
+	changed = in
+	changed.GOARCH = "arm64"
+	t.NotEqual(in.key(), changed.key())
+}
+
+func (grp *dktRunnerTests) HashFiles(t *testgroup.T) {
+	dir, err := ioutil.TempDir("", "dkt-runner-test.")
+	t.Require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "a.go")
+	t.Require.NoError(ioutil.WriteFile(file, []byte("package a\n"), 0600))
+
+	hash1, err := hashSources(dir)
+	t.Require.NoError(err)
+
+	// Tests, testdata, and other files don't affect the hash.
+	t.Require.NoError(ioutil.WriteFile(filepath.Join(dir, "a_test.go"), []byte("x"), 0600))
+	t.Require.NoError(ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("x"), 0600))
+	t.Require.NoError(os.Mkdir(filepath.Join(dir, "testdata"), 0700))
+	t.Require.NoError(ioutil.WriteFile(filepath.Join(dir, "testdata", "b.go"), []byte("x"), 0600))
+
+	hash2, err := hashSources(dir)
+	t.Require.NoError(err)
+	t.Equal(hash1, hash2)
+
+	t.Require.NoError(ioutil.WriteFile(file, []byte("package a // changed\n"), 0600))
+
+	hash3, err := hashSources(dir)
+	t.Require.NoError(err)
+	t.NotEqual(hash1, hash3)
+
+	missing, err := hashFiles([]string{filepath.Join(dir, "go.sum")})
+	t.NoError(err)
+	t.NotEmpty(missing)
+}
+
+func (grp *dktRunnerTests) CacheExecutable(t *testgroup.T) {
+	dir, err := ioutil.TempDir("", "dkt-runner-test.")
+	t.Require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	cache := dktCache{dir: dir}
+
+	builds := 0
+	build := func(dir string) (string, error) {
+		builds++
+		f, err := ioutil.TempFile(dir, "dkt.*.tmp")
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(f, "build %d", builds)
+
+		return f.Name(), f.Close()
+	}
+
+	path, built, err := cache.executable("key", false, build)
+	t.Require.NoError(err)
+	t.True(built)
+	t.Equal(1, builds)
+
+	cachedPath, built, err := cache.executable("key", false, build)
+	t.Require.NoError(err)
+	t.False(built)
+	t.Equal(path, cachedPath)
+	t.Equal(1, builds)
+
+	_, built, err = cache.executable("key", true, build)
+	t.Require.NoError(err)
+	t.True(built)
+	t.Equal(2, builds)
+
+	contents, err := ioutil.ReadFile(path)
+	t.Require.NoError(err)
+	t.Equal("build 2", string(contents))
+
+	// Only the executable is left in the key's directory.
+	infos, err := ioutil.ReadDir(filepath.Dir(path))
+	t.Require.NoError(err)
+	t.Len(infos, 1)
+
+	_, _, err = cache.executable("other", false, func(string) (string, error) {
+		return "", errors.New("build failed")
+	})
+	t.Error(err)
+}
+
+func (grp *dktRunnerTests) CachePrune(t *testgroup.T) {
+	dir, err := ioutil.TempDir("", "dkt-runner-test.")
+	t.Require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	cache := dktCache{dir: dir}
+
+	// old0 was used most recently and old6 least recently.
+	for i := 0; i < 7; i++ {
+		old := filepath.Join(dir, fmt.Sprintf("old%d", i))
+		t.Require.NoError(os.Mkdir(old, 0700))
+
+		used := time.Now().Add(-time.Duration(i+1) * time.Hour)
+		t.Require.NoError(os.Chtimes(old, used, used))
+	}
+
+	_, _, err = cache.executable("new", false, func(dir string) (string, error) {
+		f, err := ioutil.TempFile(dir, "dkt.*.tmp")
+		if err != nil {
+			return "", err
+		}
+
+		return f.Name(), f.Close()
+	})
+	t.Require.NoError(err)
+
+	infos, err := ioutil.ReadDir(dir)
+	t.Require.NoError(err)
+
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	t.Equal([]string{"new", "old0", "old1", "old2", "old3"}, names)
+}
@@ -22,10 +22,12 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strings"
 	"testing"
+	"time"
 
 	"github.com/bloomberg/docket/internal/compose"
+	"github.com/bloomberg/docket/internal/lease"
+	"github.com/bloomberg/docket/internal/logging"
 )
 
 // Context can tell you information about the active docket environment.
@@ -46,6 +48,12 @@ func (c Context) Mode() string {
 
 var ErrNoActiveTestConfig = fmt.Errorf("no active test config")
 
+// Timings returns how long each phase of the run has taken so far, in the order the phases
+// finished. It returns nil if no mode is being used.
+func (c Context) Timings() []PhaseTiming {
+	return makePhaseTimings(c.compose.Timings().Phases())
+}
+
 // PublishedPort returns the publicly exposed host port number corresponding to the privatePort for
 // a service. If that service does not publish privatePort, it will return an error.
 func (c Context) PublishedPort(ctx context.Context, service string, privatePort int) (int, error) {
@@ -84,8 +92,14 @@ func RunPrefix(ctx context.Context, docketCtx *Context, t *testing.T, prefix str
 		return
 	}
 
+	start := time.Now()
+
+	junit := newJUnitRun(t.Name(), mode)
+	defer junit.write(t)
+
 	compose, cleanup, err := compose.NewCompose(ctx, prefix, mode)
 	if err != nil {
+		junit.fail(ctx, "setup", err)
 		t.Fatalf("NewCompose failed: %v", err)
 	}
 	defer func() {
@@ -93,6 +107,9 @@ func RunPrefix(ctx context.Context, docketCtx *Context, t *testing.T, prefix str
 			t.Fatalf("failed cleanup: %v", err)
 		}
 	}()
+	defer docketTimings(t, compose, start)
+
+	junit.compose = compose
 
 	dctx := Context{
 		mode:    mode,
@@ -103,38 +120,109 @@ func RunPrefix(ctx context.Context, docketCtx *Context, t *testing.T, prefix str
 		*docketCtx = dctx
 	}
 
-	docketPull(ctx, t, compose)
-
-	if err := compose.Up(ctx); err != nil {
-		t.Fatalf("failed compose.Up: %v", err)
+	projectLease, err := docketUp(ctx, compose)
+	if err != nil {
+		junit.fail(ctx, "up", err)
+		t.Fatalf("failed to bring up the app: %v", err)
 	}
+	defer func() {
+		if err := docketDown(ctx, compose, projectLease); err != nil {
+			junit.fail(ctx, "down", err)
+			t.Fatalf("failed compose.Down: %v", err)
+		}
+	}()
+	defer junit.attachLogsIfFailed(ctx, t)
 
-	defer docketDown(ctx, t, compose)
+	if err := docketHealthCheck(ctx, compose); err != nil {
+		junit.fail(ctx, "health check", err)
+		t.Fatal(err)
+	}
 
-	if err := dctx.compose.RunTestfuncOrExecGoTest(ctx, t.Name(), testFunc); err != nil {
-		t.Fatalf("compose.RunTestfuncOrExecGoTest failed: %v", err)
+	restoreEnv, err := docketPortEnv(ctx, compose)
+	if err != nil {
+		junit.fail(ctx, "port env", err)
+		t.Fatal(err)
 	}
+	defer restoreEnv()
+
+	docketTest(ctx, t, compose, junit, testFunc)
 }
 
-func docketPull(ctx context.Context, t *testing.T, compose *compose.Compose) {
-	if os.Getenv("DOCKET_PULL") == "" {
-		return
+// phaseError is an error from a particular phase of a docket run, like "pull" or "up".
+type phaseError struct {
+	phase string
+	err   error
+}
+
+func (e *phaseError) Error() string {
+	return e.err.Error()
+}
+
+func (e *phaseError) Unwrap() error {
+	return e.err
+}
+
+// docketUp pulls (according to DOCKET_PULL_POLICY) and brings up the app while holding the project's
+// lease, so that other test processes using the same project wait their turn.
+func docketUp(ctx context.Context, compose *compose.Compose) (*lease.Lease, error) {
+	leaseDir, err := lease.Dir(compose.ProjectName())
+	if err != nil {
+		return nil, &phaseError{phase: "lease", err: fmt.Errorf("failed lease.Dir: %w", err)}
 	}
 
-	pullOpts := strings.Fields(os.Getenv("DOCKET_PULL_OPTS"))
-	if err := compose.Pull(ctx, pullOpts); err != nil {
-		t.Fatalf("failed compose.Pull: %v", err)
+	// The lease serialises setup for every process using the project, so the wait for the
+	// lease ends when setup starts.
+	start := time.Now()
+
+	projectLease, err := lease.Acquire(ctx, leaseDir, func() error {
+		compose.Timings().Record("lease wait", time.Since(start))
+
+		if err := docketPull(ctx, compose); err != nil {
+			return &phaseError{phase: "pull", err: err}
+		}
+
+		if err := docketBuild(ctx, compose); err != nil {
+			return err
+		}
+
+		if err := compose.Up(ctx); err != nil {
+			return &phaseError{phase: "up", err: fmt.Errorf("failed compose.Up: %w", err)}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+
+	return projectLease, nil
 }
 
-func docketDown(ctx context.Context, t *testing.T, compose *compose.Compose) {
-	if os.Getenv("DOCKET_DOWN") == "" {
-		fmt.Printf("leaving docker-compose app running...\n")
+// docketDown releases the project's lease. If DOCKET_DOWN is set and no other test process
+// holds the lease, it takes down the app.
+func docketDown(ctx context.Context, compose *compose.Compose, projectLease *lease.Lease) error {
+	down := os.Getenv("DOCKET_DOWN") != ""
 
-		return
+	others, err := projectLease.Release(ctx, func() error {
+		if !down {
+			return nil
+		}
+
+		return compose.Down(ctx)
+	})
+	if err != nil {
+		return err
 	}
 
-	if err := compose.Down(ctx); err != nil {
-		t.Fatalf("failed compose.Down: %v", err)
+	fields := []logging.Field{logging.Mode(compose.Mode()), logging.Project(compose.ProjectName())}
+
+	switch {
+	case others > 0:
+		logging.Get().Log(logging.LevelInfo, "leaving docker-compose app running",
+			append(fields, logging.Field{Key: "other_holders", Value: others})...)
+	case !down:
+		logging.Get().Log(logging.LevelInfo, "leaving docker-compose app running", fields...)
 	}
+
+	return nil
 }
@@ -0,0 +1,43 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docket
+
+import (
+	"context"
+	"time"
+
+	"github.com/bloomberg/docket/internal/compose"
+)
+
+const defaultHealthTimeout = time.Minute
+
+// docketHealthCheck checks that the services are running (and healthy, if they have
+// healthchecks) after they come up, unless DOCKET_HEALTH_TIMEOUT is 0.
+func docketHealthCheck(ctx context.Context, compose *compose.Compose) error {
+	timeout, err := durationFromEnv("DOCKET_HEALTH_TIMEOUT", defaultHealthTimeout)
+	if err != nil {
+		return &phaseError{phase: "health check", err: err}
+	}
+
+	if timeout == 0 {
+		return nil
+	}
+
+	if err := compose.CheckHealth(ctx, timeout); err != nil {
+		return &phaseError{phase: "health check", err: err}
+	}
+
+	return nil
+}
@@ -38,12 +38,79 @@ Help for using docket:
 
 Optional environment variables:
 
+  {{ var "DOCKET_ARTIFACTS_DIR" }} (default none)
+    If non-empty, docket will write a JSON file with how long each phase of each docket run
+    took into this directory (and DOCKET_JUNIT's reports).
+
+  {{ var "DOCKET_BUILD_ARGS" }} (default none)
+    A comma-separated list of host environment variables that docket passes as build args
+    when it builds the images of services with a build section.
+
+  {{ var "DOCKET_BUILD_NO_CACHE" }} (default off)
+    If non-empty, docket will build images with --no-cache.
+
+  {{ var "DOCKET_BUILD_PARALLEL" }} (default off)
+    If non-empty, docket will build images with --parallel.
+
+  {{ var "DOCKET_BUILD_PULL" }} (default off)
+    If non-empty, docket will build images with --pull.
+
   {{ var "DOCKET_DOWN" }} (default off)
     If non-empty, docket will run 'docker-compose down' at the end of each docket run.
 
+  {{ var "DOCKET_FORWARD_ENV" }} (default none)
+    A comma-separated list of host environment variables (e.g., GOPROXY,GOPRIVATE,GOFLAGS) that
+    docket passes to services that run go test or mount go sources. Add NETRC to the list to
+    mount your netrc file too.
+
+  {{ var "DOCKET_HEALTH_TIMEOUT" }} (default 1m)
+    How long docket waits for services' healthchecks after bringing up the app. Docket fails
+    the test if a service exited, is restarting, or is unhealthy. 0 skips the check.
+
+  {{ var "DOCKET_IGNORE_LOCK" }} (default off)
+    If non-empty, docket will ignore the lock file that 'dkt lock' writes and use the images
+    in the docket files as they are.
+
+  {{ var "DOCKET_JUNIT" }} (default off)
+    If non-empty, docket will write a JUnit XML report for each docket run into
+    DOCKET_ARTIFACTS_DIR.
+
+  {{ var "DOCKET_LOG" }} (default text)
+    How docket logs what it's doing to stderr: text, json, or quiet.
+
+  {{ var "DOCKET_LOG_LEVEL" }} (default info)
+    The least important level that docket logs: debug, info, warn, or error.
+
+  {{ var "DOCKET_MONITOR" }} (default off)
+    If non-empty, docket will check that the services are still running while the test runs.
+
+  {{ var "DOCKET_MONITOR_INTERVAL" }} (default 2s)
+    How often DOCKET_MONITOR checks the services. 0 turns off the checks.
+
+  {{ var "DOCKET_PORT_ENV" }} (default off)
+    If non-empty, docket will set an environment variable like DOCKET_REDIS_6379_ADDR to the
+    host address of each published port while the test runs.
+
+  {{ var "DOCKET_PORT_ENV_FORMAT" }} (default DOCKET_{{ "{{.Service}}_{{.Port}}" }}_ADDR)
+    A text/template for the names of DOCKET_PORT_ENV's variables. It can use .Service, .Port,
+    and .Protocol.
+
   {{ var "DOCKET_PULL" }} (default off)
     If non-empty, docket will run 'docker-compose pull' at the start of each docket run.
 
+  {{ var "DOCKET_PULL_POLICY" }} (default none)
+    Which images docket pulls at the start of each docket run: missing (the images that aren't
+    present locally), always (like DOCKET_PULL), or never (fail if an image isn't present).
+
+  {{ var "DOCKET_PULL_RETRIES" }} (default 2)
+    How many times docket retries a failed 'docker-compose pull'.
+
+  {{ var "DOCKET_STATE_DIR" }} (default docket/state in the user cache directory)
+    Where docket keeps the files that coordinate test processes and the files it generates.
+
+  {{ var "DOCKET_TIMINGS" }} (default off)
+    If non-empty, docket will print how long each phase took at the end of each docket run.
+
 `[1:])).Execute(out, nil)
 	if err != nil {
 		panic(fmt.Sprintf("failed to Execute help template: %v", err))
@@ -0,0 +1,141 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/bloomberg/docket/internal/logging"
+)
+
+// buildErrorLines is how many lines of `docker-compose build`'s output a BuildError includes.
+const buildErrorLines = 20
+
+// BuildOptions are the options docket passes to `docker-compose build`.
+type BuildOptions struct {
+	NoCache  bool     // --no-cache
+	Pull     bool     // --pull, to pull newer versions of base images
+	Args     []string // environment variables to pass as --build-arg
+	Parallel bool     // --parallel
+}
+
+// BuildOptionsFromEnv reads BuildOptions from DOCKET_BUILD_NO_CACHE, DOCKET_BUILD_PULL,
+// DOCKET_BUILD_ARGS, and DOCKET_BUILD_PARALLEL.
+func BuildOptionsFromEnv() BuildOptions {
+	return BuildOptions{
+		NoCache:  os.Getenv("DOCKET_BUILD_NO_CACHE") != "",
+		Pull:     os.Getenv("DOCKET_BUILD_PULL") != "",
+		Args:     parseForwardEnv(os.Getenv("DOCKET_BUILD_ARGS")),
+		Parallel: os.Getenv("DOCKET_BUILD_PARALLEL") != "",
+	}
+}
+
+// makeBuildArgs makes `docker-compose build`'s arguments. Build args only name the variables,
+// so docker-compose passes their values through from the environment; variables that aren't set
+// are skipped.
+func makeBuildArgs(opts BuildOptions, lookupEnv func(string) (string, bool)) []string {
+	var args []string
+
+	if opts.NoCache {
+		args = append(args, "--no-cache")
+	}
+	if opts.Pull {
+		args = append(args, "--pull")
+	}
+	if opts.Parallel {
+		args = append(args, "--parallel")
+	}
+
+	for _, name := range opts.Args {
+		if _, ok := lookupEnv(name); ok {
+			args = append(args, "--build-arg", name)
+		}
+	}
+
+	return args
+}
+
+// BuiltServices returns the services with a build section, sorted.
+func (c Compose) BuiltServices() []string {
+	var services []string
+
+	for name, svc := range c.cfg.Services {
+		if svc.Build != nil {
+			services = append(services, name)
+		}
+	}
+
+	sort.Strings(services)
+
+	return services
+}
+
+// BuildCommand makes the `docker-compose build` command with opts. The rest of args (like
+// service names) come after opts.
+func (c Compose) BuildCommand(ctx context.Context, opts BuildOptions, args ...string) *exec.Cmd {
+	buildArgs := append([]string{"build"}, makeBuildArgs(opts, os.LookupEnv)...)
+
+	return c.Command(ctx, append(buildArgs, args...)...)
+}
+
+// BuildError reports that `docker-compose build` failed.
+type BuildError struct {
+	Err    error
+	Output []string // the last lines of the output
+}
+
+func (e *BuildError) Error() string {
+	msg := fmt.Sprintf("failed to build images: %v", e.Err)
+	if len(e.Output) > 0 {
+		msg += "; the last lines of docker-compose build:\n" + strings.Join(e.Output, "\n")
+	}
+
+	return msg
+}
+
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
+
+// Build runs `docker-compose build` for the services with a build section and logs its output.
+// If it fails, it returns a *BuildError.
+func (c Compose) Build(ctx context.Context, opts BuildOptions) error {
+	services := c.BuiltServices()
+	if len(services) == 0 {
+		return nil
+	}
+
+	cmd := c.BuildCommand(ctx, opts, services...)
+
+	out := c.newLogWriter("build", buildErrorLines)
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	defer c.logStep("build", logging.Command(cmd.Args))()
+
+	err := cmd.Run()
+	out.Flush()
+
+	if err != nil {
+		return &BuildError{Err: err, Output: out.Tail()}
+	}
+
+	return nil
+}
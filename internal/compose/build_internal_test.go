@@ -0,0 +1,104 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/bloomberg/docket/internal/logging"
+	"github.com/stretchr/testify/suite"
+)
+
+func Test_Build(t *testing.T) {
+	suite.Run(t, new(BuildSuite))
+}
+
+type BuildSuite struct {
+	suite.Suite
+}
+
+func (s *BuildSuite) Test_makeBuildArgs() {
+	lookupEnv := func(name string) (string, bool) {
+		if name == "GOPROXY" {
+			return "https://proxy.example.com", true
+		}
+
+		return "", false
+	}
+
+	s.Empty(makeBuildArgs(BuildOptions{NoCache: false, Pull: false, Args: nil, Parallel: false},
+		lookupEnv))
+
+	s.Equal([]string{"--no-cache", "--pull", "--parallel", "--build-arg", "GOPROXY"},
+		makeBuildArgs(BuildOptions{
+			NoCache:  true,
+			Pull:     true,
+			Args:     []string{"GOPROXY", "UNSET"},
+			Parallel: true,
+		}, lookupEnv))
+}
+
+func (s *BuildSuite) Test_BuiltServices() {
+	c := Compose{cfg: cmpConfig{Services: map[string]cmpService{
+		"redis": {Image: "redis:6"},
+		"app":   {Image: "example/app", Build: "."},
+		"built": {Build: map[interface{}]interface{}{"context": "."}},
+	}}}
+
+	s.Equal([]string{"app", "built"}, c.BuiltServices())
+}
+
+func (s *BuildSuite) Test_BuildError() {
+	err := &BuildError{Err: errors.New("exit status 1"), Output: []string{"step 1", "oops"}}
+
+	s.Equal("failed to build images: exit status 1; the last lines of docker-compose build:\n"+
+		"step 1\noops", err.Error())
+	s.Equal("failed to build images: exit status 1",
+		(&BuildError{Err: errors.New("exit status 1"), Output: nil}).Error())
+}
+
+func (s *BuildSuite) Test_logWriter() {
+	var mu sync.Mutex
+	var lines []string
+
+	logging.Set(logging.LoggerFunc(func(level logging.Level, msg string, fields ...logging.Field) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for _, f := range fields {
+			if f.Key == logging.KeyOutput {
+				lines = append(lines, fmt.Sprintf("%s: %v", msg, f.Value))
+			}
+		}
+	}))
+	defer logging.Set(nil)
+
+	w := Compose{}.newLogWriter("build", 2)
+
+	_, err := fmt.Fprint(w, "one\r\ntw")
+	s.Require().NoError(err)
+	_, err = fmt.Fprint(w, "o\nthree")
+	s.Require().NoError(err)
+
+	s.Equal([]string{"build: one", "build: two"}, lines)
+
+	w.Flush()
+
+	s.Equal([]string{"build: one", "build: two", "build: three"}, lines)
+	s.Equal([]string{"two", "three"}, w.Tail())
+}
@@ -17,41 +17,80 @@ package compose
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/bloomberg/docket/internal/logging"
+	"github.com/bloomberg/docket/internal/statedir"
 	"gopkg.in/yaml.v2"
 )
 
 // Compose represents a call to docker-compose.
 type Compose struct {
 	baseArgs []string
+	cfg      cmpConfig
 
-	testSvc string
+	mode           string
+	projectDir     string   // the absolute path of the directory with the docket files
+	files          []string // the docket files for mode
+	generatedFiles []string // files generated by docket, which come after files
+
+	testSvc       string
+	testBinaryDir string // non-empty if testSvc runs a test binary built on the host
+
+	timings *Timings // shared by copies of the Compose
 }
 
 // NewCompose returns a new Compose and cleanup function given a context, prefix, and mode.
 func NewCompose(ctx context.Context, prefix, mode string) (
 	cmp *Compose, cleanup func() error, err error,
 ) {
-	cmp = &Compose{}
+	cmp = &Compose{mode: mode, timings: &Timings{}}
 	cleanup = func() error { return nil }
 
-	cmp.baseArgs, err = makeDocketFileArgs(prefix, mode)
+	cmp.files, err = findDocketFiles(prefix, mode)
 	if err != nil {
 		return nil, cleanup, err
 	}
 
+	// docker-compose resolves relative paths from the project directory, which would otherwise
+	// be the directory of the first file. Setting it keeps them relative to the docket files no
+	// matter where docket generates its files.
+	cmp.projectDir, err = filepath.Abs(filepath.Dir(cmp.files[0]))
+	if err != nil {
+		return nil, cleanup, fmt.Errorf("failed filepath.Abs: %w", err)
+	}
+	cmp.baseArgs = append([]string{"--project-directory", cmp.projectDir}, makeFileArgs(cmp.files)...)
+
+	recordConfig := cmp.timings.Start("config")
 	cfg, err := cmp.getAndParseConfig(ctx)
 	if err != nil {
 		return nil, cleanup, err
 	}
+	cmp.cfg = cfg
+	recordConfig()
+
+	lockFiles, lockCleanup, err := doImageLock(cfg, filepath.Join(cmp.projectDir, LockFile(prefix)))
+	cleanup = chainCleanups(cleanup, lockCleanup)
+	if err != nil {
+		return nil, cleanup, err
+	}
 
+	cmp.generatedFiles = append(cmp.generatedFiles, lockFiles...)
+	cmp.baseArgs = append(cmp.baseArgs, makeFileArgs(lockFiles)...)
+
+	recordGoList := cmp.timings.Start("go list")
 	goList, err := runGoList(ctx)
 	if err != nil {
 		return nil, cleanup, err
@@ -62,22 +101,124 @@ func NewCompose(ctx context.Context, prefix, mode string) (
 		return nil, cleanup, err
 	}
 
-	mountsArgs, mountsCleanup, err := doSourceMounts(cfg, goList, goPath)
+	cmp.testSvc, err = findSingleTestService(cfg)
 	if err != nil {
 		return nil, cleanup, err
 	}
 
-	cmp.baseArgs = append(cmp.baseArgs, mountsArgs...)
-	cleanup = chainCleanups(cleanup, mountsCleanup)
+	if label, _ := parseDocketLabel(cfg.Services[cmp.testSvc]); label.testBinary {
+		pkgDir, err := os.Getwd()
+		if err != nil {
+			return nil, cleanup, fmt.Errorf("failed to get current dir: %w", err)
+		}
 
-	cmp.testSvc, err = findSingleTestService(cfg)
+		if cmp.testBinaryDir, err = makeTestBinaryDir(cmp.ProjectName(), pkgDir); err != nil {
+			return nil, cleanup, err
+		}
+	}
+
+	workspace, err := findGoWorkspace(ctx, goList)
 	if err != nil {
 		return nil, cleanup, err
 	}
+	recordGoList()
+
+	recordMounts := cmp.timings.Start("source mounts")
+	mountsFiles, mountsCleanup, err := doSourceMounts(
+		cfg, cmp.ProjectName(), goList, goPath, workspace, cmp.testBinaryDir)
+	cleanup = chainCleanups(cleanup, mountsCleanup)
+	if err != nil {
+		return nil, cleanup, err
+	}
+	recordMounts()
+
+	cmp.generatedFiles = append(cmp.generatedFiles, mountsFiles...)
+	cmp.baseArgs = append(cmp.baseArgs, makeFileArgs(mountsFiles)...)
 
 	return cmp, cleanup, nil
 }
 
+// Timings returns the Timings that record how long each phase takes.
+func (c Compose) Timings() *Timings {
+	return c.timings
+}
+
+// Mode returns the docket mode.
+func (c Compose) Mode() string {
+	return c.mode
+}
+
+// Files returns the files passed to docker-compose, in order. Files generated by docket come
+// after the docket files for the mode.
+func (c Compose) Files() []string {
+	files := make([]string, 0, len(c.files)+len(c.generatedFiles))
+	files = append(files, c.files...)
+	files = append(files, c.generatedFiles...)
+
+	return files
+}
+
+// GeneratedFiles returns the files docket generated and passes to docker-compose.
+func (c Compose) GeneratedFiles() []string {
+	return append([]string(nil), c.generatedFiles...)
+}
+
+// ProjectName returns the name docker-compose uses for the project, which it uses to name
+// containers, networks, and volumes.
+func (c Compose) ProjectName() string {
+	return ProjectNameForDir(c.projectDir)
+}
+
+// ProjectNameForDir returns the name docker-compose uses for the project of the docket files
+// in dir.
+func ProjectNameForDir(dir string) string {
+	name := os.Getenv("COMPOSE_PROJECT_NAME")
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+
+	return normalizeProjectName(name)
+}
+
+// TestService returns the name of the service that runs the tests, or a blank string if the
+// tests run on the host.
+func (c Compose) TestService() string {
+	return c.testSvc
+}
+
+// SourceMountServices returns the services, other than the test service, that are labeled
+// "mount go sources".
+func (c Compose) SourceMountServices() []string {
+	var services []string
+	for name, svc := range c.cfg.Services {
+		if label, _ := parseDocketLabel(svc); label.mountGoSources && !label.runGoTest {
+			services = append(services, name)
+		}
+	}
+
+	sort.Strings(services)
+
+	return services
+}
+
+// WorkingDir returns the working directory of a service. For services that get Go sources
+// mounted, this is the package's directory inside the container.
+func (c Compose) WorkingDir(ctx context.Context, service string) (string, error) {
+	cfg, err := c.getAndParseConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	svc, ok := cfg.Services[service]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", errNoSuchService, service)
+	}
+
+	return svc.WorkingDir, nil
+}
+
+var errNoSuchService = fmt.Errorf("no such service")
+
 // Command makes an *exec.Cmd that calls `docker-compose` with the right arguments and environment.
 //
 // Command is intended to be a helper function. It is exported mainly so `dkt` can use it.
@@ -97,8 +238,7 @@ func (c Compose) Down(ctx context.Context) error {
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	tracef("down %v\n", cmd.Args)
-	defer tracef("down finished\n")
+	defer c.logStep("down", logging.Command(cmd.Args))()
 
 	return cmd.Run()
 }
@@ -107,7 +247,7 @@ func (c Compose) Down(ctx context.Context) error {
 func (c Compose) GetConfig(ctx context.Context) ([]byte, error) {
 	cmd := c.Command(ctx, "config")
 
-	tracef("config %v\n", cmd.Args)
+	c.log(logging.LevelInfo, "config", logging.Command(cmd.Args))
 
 	out, err := cmd.CombinedOutput()
 	if err != nil {
@@ -117,13 +257,67 @@ func (c Compose) GetConfig(ctx context.Context) ([]byte, error) {
 	return out, nil
 }
 
+// SaveConfig writes the aggregated Compose file to docket's state directory and returns a copy
+// of c that passes only that file to docker-compose. Unlike the files docket generates for each
+// run, the saved file stays put after docket cleans up, so `dkt test --print` can print commands
+// that still work after it exits. The path is the same for every run of a package in a mode.
+func (c Compose) SaveConfig(ctx context.Context) (*Compose, error) {
+	cmd := c.Command(ctx, "config")
+
+	c.log(logging.LevelInfo, "config", logging.Command(cmd.Args))
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error getting config: %w", err)
+	}
+
+	pkgDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current dir: %w", err)
+	}
+
+	h := sha256.Sum256([]byte(c.ProjectName() + "\x00" + c.mode + "\x00" + pkgDir))
+
+	dir, err := statedir.Dir("saved-configs", hex.EncodeToString(h[:])[:16])
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, "docker-compose.yaml")
+	if err := writeFileIfChanged(path, out); err != nil {
+		return nil, fmt.Errorf("failed to save config: %w", err)
+	}
+
+	saved := c
+	saved.files, saved.generatedFiles = []string{path}, nil
+	saved.baseArgs = append(
+		[]string{"--project-directory", c.projectDir}, makeFileArgs(saved.files)...)
+
+	return &saved, nil
+}
+
+// Logs calls `docker-compose logs` and returns the last tail lines of the services' logs.
+func (c Compose) Logs(ctx context.Context, tail int, service ...string) ([]byte, error) {
+	cmd := c.Command(ctx, "logs", "--no-color", "--tail", strconv.Itoa(tail))
+	cmd.Args = append(cmd.Args, service...)
+
+	c.log(logging.LevelInfo, "logs", logging.Command(cmd.Args))
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error getting logs: err=%w out=%s", err, out)
+	}
+
+	return out, nil
+}
+
 var errPortNotFound = fmt.Errorf("port not found")
 
 // GetPort runs `docker-compose port` and returns the public port for a service's port binding.
 func (c Compose) GetPort(ctx context.Context, service string, port int) (int, error) {
 	cmd := c.Command(ctx, "port", service, strconv.Itoa(port))
 
-	tracef("port %v\n", cmd.Args)
+	c.log(logging.LevelInfo, "port", logging.Service(service), logging.Command(cmd.Args))
 
 	out, err := cmd.CombinedOutput()
 	if err != nil {
@@ -147,18 +341,24 @@ func (c Compose) Pull(ctx context.Context, args []string) error {
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	tracef("pull %v\n", cmd.Args)
-	defer tracef("pull finished\n")
+	defer c.logStep("pull", logging.Command(cmd.Args))()
 
 	return cmd.Run()
 }
 
 // RunTestfuncOrExecGoTest either calls testFunc directly or runs `docker-compose exec` to re-run
 // `go test` inside the appropriate service (container).
+//
+// If the test service is labeled "run test binary", the test binary is built on the host and
+// run inside the service instead of `go test`.
+//
+// If results is non-nil, the tests inside the service run verbosely and results collects their
+// output and results.
 func (c Compose) RunTestfuncOrExecGoTest(
-	ctx context.Context, testName string, testFunc func(),
+	ctx context.Context, testName string, testFunc func(), results *TestResults,
 ) error {
 	if c.testSvc == "" {
+		defer c.timings.Start("test")()
 		testFunc()
 
 		return nil
@@ -169,25 +369,31 @@ func (c Compose) RunTestfuncOrExecGoTest(
 		originalTestRunArg = f.Value.String()
 	}
 
-	args := []string{
-		"exec",
-		"-T", // disable pseudo-tty allocation
-		c.testSvc,
-		"go", "test",
-		"-run", makeRunArgForTest(testName, originalTestRunArg),
+	runArg := makeRunArgForTest(testName, originalTestRunArg)
+
+	var testArgs []string
+	if testing.Verbose() || results != nil {
+		testArgs = append(testArgs, "-v")
 	}
 
-	if testing.Verbose() {
-		args = append(args, "-v")
+	var binaryPath string
+	if c.UsesTestBinary() {
+		var err error
+		if binaryPath, err = c.BuildTestBinary(ctx); err != nil {
+			return err
+		}
 	}
 
-	cmd := c.Command(ctx, args...)
+	cmd := c.ExecGoTestCommand(ctx, binaryPath, runArg, testArgs)
 
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
+	if results != nil {
+		cmd.Stdout = io.MultiWriter(os.Stdout, results)
+		cmd.Stderr = io.MultiWriter(os.Stderr, results)
+	}
 
-	tracef("exec %v\n", cmd.Args)
-	defer tracef("exec finished\n")
+	defer c.logStep("exec", logging.Service(c.testSvc), logging.Command(cmd.Args))()
 
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to exec go test: %w", err)
@@ -196,6 +402,35 @@ func (c Compose) RunTestfuncOrExecGoTest(
 	return nil
 }
 
+// ExecGoTestCommand makes the `docker-compose exec` command that runs the tests inside the test
+// service. runArg is passed as -run if it isn't empty, and testArgs are passed on to `go test`.
+//
+// If the test service runs a test binary (see UsesTestBinary), binaryPath is the binary's path
+// inside the service, and testArgs are translated into the binary's -test.* flags.
+func (c Compose) ExecGoTestCommand(
+	ctx context.Context, binaryPath, runArg string, testArgs []string,
+) *exec.Cmd {
+	args := []string{
+		"exec",
+		"-T", // disable pseudo-tty allocation
+		c.testSvc,
+	}
+
+	if c.UsesTestBinary() {
+		args = append(args, makeTestBinaryArgs(binaryPath, runArg, testArgs)...)
+	} else {
+		args = append(args, makeGoTestArgs(runArg, testArgs)...)
+	}
+
+	return c.Command(ctx, args...)
+}
+
+// RunArgForTest returns the -run argument that re-runs exactly the test named testName inside
+// the test service. runArg is the -run argument (if any) that selected the test on the host.
+func RunArgForTest(testName, runArg string) string {
+	return makeRunArgForTest(testName, runArg)
+}
+
 // Up calls `docker-compose up`.
 func (c Compose) Up(ctx context.Context, service ...string) error {
 	cmd := c.Command(ctx, "up", "-d")
@@ -203,8 +438,7 @@ func (c Compose) Up(ctx context.Context, service ...string) error {
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	tracef("up %v\n", cmd.Args)
-	defer tracef("up finished\n")
+	defer c.logStep("up", logging.Command(cmd.Args))()
 
 	return cmd.Run()
 }
@@ -212,18 +446,21 @@ func (c Compose) Up(ctx context.Context, service ...string) error {
 //------------------------------------------------------------------------------
 
 type cmpVolume struct {
-	Type   string `yaml:"type"`
-	Source string `yaml:"source"`
-	Target string `yaml:"target"`
+	Type     string `yaml:"type"`
+	Source   string `yaml:"source"`
+	Target   string `yaml:"target"`
+	ReadOnly bool   `yaml:"read_only,omitempty"`
 }
 
 type cmpService struct {
-	Command     interface{}       `yaml:"command,omitempty"` // []string or just a string
-	Environment map[string]string `yaml:"environment,omitempty"`
-	Image       string            `yaml:"image,omitempty"`
-	Labels      map[string]string `yaml:"labels,omitempty"`
-	Volumes     []cmpVolume       `yaml:"volumes,omitempty"`
-	WorkingDir  string            `yaml:"working_dir,omitempty"`
+	Build       interface{}        `yaml:"build,omitempty"`       // a context path or a map
+	Command     interface{}        `yaml:"command,omitempty"`     // []string or just a string
+	Environment map[string]*string `yaml:"environment,omitempty"` // nil values come from the host
+	Image       string             `yaml:"image,omitempty"`
+	Labels      map[string]string  `yaml:"labels,omitempty"`
+	Ports       []interface{}      `yaml:"ports,omitempty"` // strings or maps
+	Volumes     []cmpVolume        `yaml:"volumes,omitempty"`
+	WorkingDir  string             `yaml:"working_dir,omitempty"`
 }
 
 type cmpConfig struct {
@@ -250,7 +487,7 @@ func (c Compose) getAndParseConfig(ctx context.Context) (cmpConfig, error) {
 
 var errNoMatchingDocketFiles = fmt.Errorf("no matching docket files found")
 
-func makeDocketFileArgs(prefix, mode string) ([]string, error) {
+func findDocketFiles(prefix, mode string) ([]string, error) {
 	files, err := findAndSortDocketFiles(prefix, mode)
 	if err != nil {
 		return nil, err
@@ -260,13 +497,17 @@ func makeDocketFileArgs(prefix, mode string) ([]string, error) {
 		return nil, fmt.Errorf("%w: prefix=%s, mode=%s", errNoMatchingDocketFiles, prefix, mode)
 	}
 
+	return files, nil
+}
+
+func makeFileArgs(files []string) []string {
 	const sizeOfArgPair = 2
 	args := make([]string, 0, len(files)*sizeOfArgPair)
 	for _, f := range files {
 		args = append(args, "--file", f)
 	}
 
-	return args, nil
+	return args
 }
 
 var errMultipleTestServices = fmt.Errorf("multiple test services found")
@@ -274,9 +515,9 @@ var errMultipleTestServices = fmt.Errorf("multiple test services found")
 func findSingleTestService(cfg cmpConfig) (string, error) {
 	testSvc := ""
 	for name, svc := range cfg.Services {
-		if runGoTest, _, err := parseDocketLabel(svc); err != nil {
+		if label, err := parseDocketLabel(svc); err != nil {
 			return "", err
-		} else if runGoTest {
+		} else if label.runGoTest {
 			if testSvc != "" {
 				return "",
 					fmt.Errorf("%w (at least %q and %q)", errMultipleTestServices, testSvc, name)
@@ -290,23 +531,75 @@ func findSingleTestService(cfg cmpConfig) (string, error) {
 
 var errUnrecognizedDocketLabel = fmt.Errorf("unrecognized docket label")
 
-func parseDocketLabel(svc cmpService) (runGoTest bool, mountGoSources bool, err error) {
-	const docketLabelKey = "com.bloomberg.docket"
+type docketLabel struct {
+	runGoTest      bool // the service is where the tests run
+	testBinary     bool // the tests run from a test binary built on the host instead of `go test`
+	mountGoSources bool
+	expectExit     bool // the service can exit while the tests run, like a one-shot job
+}
+
+const (
+	docketLabelKey     = "com.bloomberg.docket"
+	expectExitLabelKey = "com.bloomberg.docket.expect-exit"
+)
+
+func parseDocketLabel(svc cmpService) (docketLabel, error) {
+	label, err := parseDocketLabelValue(svc.Labels[docketLabelKey])
+	if err != nil {
+		return docketLabel{}, err
+	}
+
+	label.expectExit, err = parseExpectExitLabelValue(svc.Labels[expectExitLabelKey])
+	if err != nil {
+		return docketLabel{}, err
+	}
+
+	return label, nil
+}
+
+func parseDocketLabelValue(labelData string) (docketLabel, error) {
+	label := docketLabel{runGoTest: false, testBinary: false, mountGoSources: false, expectExit: false}
 
-	labelData := svc.Labels[docketLabelKey]
 	switch labelData {
 	case "":
-		return false, false, nil
+		return label, nil
 	case "run go test":
-		return true, true, nil
+		label.runGoTest, label.mountGoSources = true, true
+
+		return label, nil
+	case "run test binary":
+		label.runGoTest, label.testBinary, label.mountGoSources = true, true, true
+
+		return label, nil
 	case "mount go sources":
-		return false, true, nil
+		label.mountGoSources = true
+
+		return label, nil
 	}
 
-	return false, false,
+	return docketLabel{},
 		fmt.Errorf("%w: %q : %q", errUnrecognizedDocketLabel, docketLabelKey, labelData)
 }
 
+func parseExpectExitLabelValue(labelData string) (bool, error) {
+	switch labelData {
+	case "", "false":
+		return false, nil
+	case "true":
+		return true, nil
+	}
+
+	return false,
+		fmt.Errorf("%w: %q : %q", errUnrecognizedDocketLabel, expectExitLabelKey, labelData)
+}
+
+var nonProjectNameChars = regexp.MustCompile(`[^-_a-z0-9]`)
+
+// normalizeProjectName normalizes a project name the same way docker-compose does.
+func normalizeProjectName(name string) string {
+	return nonProjectNameChars.ReplaceAllString(strings.ToLower(name), "")
+}
+
 func chainCleanups(a, b func() error) func() error {
 	return func() error {
 		if err := a(); err != nil {
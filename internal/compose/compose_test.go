@@ -112,7 +112,7 @@ func (s *ComposeSuite) Test_RunTestsLocally() {
 	s.NoError(err)
 	s.Require().NotNil(cmp)
 
-	s.NoError(cmp.RunTestfuncOrExecGoTest(s.ctx, "testName", func() {}))
+	s.NoError(cmp.RunTestfuncOrExecGoTest(s.ctx, "testName", func() {}, nil))
 }
 
 func (s *ComposeSuite) Test_RunTestfuncOrExecGoTest() {
@@ -127,7 +127,7 @@ func (s *ComposeSuite) Test_RunTestfuncOrExecGoTest() {
 	// This should run the testName inside the container, not run the function locally.
 	s.NoError(cmp.RunTestfuncOrExecGoTest(s.ctx, "TestHelloWorld", func() {
 		s.Fail("This function should not have been called!")
-	}))
+	}, nil))
 }
 
 func (s *ComposeSuite) Test_RunTestfuncOrExecGoTest_StringCommand() {
@@ -142,7 +142,7 @@ func (s *ComposeSuite) Test_RunTestfuncOrExecGoTest_StringCommand() {
 	// This should run the testName inside the container, not run the function locally.
 	s.NoError(cmp.RunTestfuncOrExecGoTest(s.ctx, "TestHelloWorld", func() {
 		s.Fail("This function should not have been called!")
-	}))
+	}, nil))
 }
 
 func (s *ComposeSuite) Test_RunTestfuncOrExecGoTest_FailsWithABadPath() {
@@ -154,7 +154,22 @@ func (s *ComposeSuite) Test_RunTestfuncOrExecGoTest_FailsWithABadPath() {
 	s.Require().NoError(cmp.Up(s.ctx))
 	defer func() { s.Require().NoError(cmp.Down(s.ctx)) }()
 
-	err = cmp.RunTestfuncOrExecGoTest(s.ctx, "testName", func() {})
+	err = cmp.RunTestfuncOrExecGoTest(s.ctx, "testName", func() {}, nil)
 	s.Error(err)
 	s.Regexp("failed to exec go test", err)
 }
+
+func (s *ComposeSuite) Test_RunTestfuncOrExecGoTest_TestBinary() {
+	cmp, cleanup, err := compose.NewCompose(s.ctx, "docket.test-service", "test-binary")
+	defer func() { s.NoError(cleanup()) }()
+	s.NoError(err)
+	s.Require().NotNil(cmp)
+
+	s.Require().NoError(cmp.Up(s.ctx))
+	defer func() { s.NoError(cmp.Down(s.ctx)) }()
+
+	// The service's image has no Go toolchain, so this only works if we run a test binary.
+	s.NoError(cmp.RunTestfuncOrExecGoTest(s.ctx, "TestHelloWorld", func() {
+		s.Fail("This function should not have been called!")
+	}, nil))
+}
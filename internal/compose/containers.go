@@ -0,0 +1,131 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/bloomberg/docket/internal/logging"
+)
+
+// ContainerState is the state of one of the app's containers.
+type ContainerState struct {
+	Service      string
+	Container    string
+	Status       string // "created", "running", "restarting", "exited", "dead", ...
+	Health       string // "starting", "healthy", "unhealthy", or "" without a healthcheck
+	ExitCode     int
+	RestartCount int
+	ExpectExit   bool // the service is labeled to expect it to exit
+}
+
+// Stopped returns whether the container isn't running (or trying to).
+func (s ContainerState) Stopped() bool {
+	return s.Status != "running" && s.Status != "restarting"
+}
+
+// dockerInspectContainer is the part of `docker inspect`'s output that docket uses.
+type dockerInspectContainer struct {
+	Name  string
+	State struct {
+		Status   string
+		ExitCode int
+		Health   *struct {
+			Status string
+		}
+	}
+	RestartCount int
+	Config       struct {
+		Labels map[string]string
+	}
+}
+
+const composeServiceLabelKey = "com.docker.compose.service"
+
+// ContainerStates runs `docker-compose ps` and `docker inspect` to find the state of each of
+// the app's containers, including stopped ones. The states are sorted by service.
+func (c Compose) ContainerStates(ctx context.Context) ([]ContainerState, error) {
+	cmd := c.Command(ctx, "ps", "-a", "-q")
+
+	c.log(logging.LevelDebug, "ps", logging.Command(cmd.Args))
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed docker-compose ps: %w", err)
+	}
+
+	ids := strings.Fields(string(out))
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	cmd = exec.CommandContext(ctx, "docker", append([]string{"inspect"}, ids...)...)
+
+	c.log(logging.LevelDebug, "inspect", logging.Command(cmd.Args))
+
+	out, err = cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed docker inspect: %w", err)
+	}
+
+	return c.parseContainerStates(out)
+}
+
+func (c Compose) parseContainerStates(inspectOutput []byte) ([]ContainerState, error) {
+	var containers []dockerInspectContainer
+	if err := json.Unmarshal(inspectOutput, &containers); err != nil {
+		return nil, fmt.Errorf("failed to parse docker inspect output: %w", err)
+	}
+
+	states := make([]ContainerState, 0, len(containers))
+	for _, ctr := range containers {
+		service := ctr.Config.Labels[composeServiceLabelKey]
+
+		label, err := parseDocketLabel(c.cfg.Services[service])
+		if err != nil {
+			return nil, err
+		}
+
+		health := ""
+		if ctr.State.Health != nil {
+			health = ctr.State.Health.Status
+		}
+
+		states = append(states, ContainerState{
+			Service:      service,
+			Container:    strings.TrimPrefix(ctr.Name, "/"),
+			Status:       ctr.State.Status,
+			Health:       health,
+			ExitCode:     ctr.State.ExitCode,
+			RestartCount: ctr.RestartCount,
+			ExpectExit:   label.expectExit,
+		})
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].Service != states[j].Service {
+			return states[i].Service < states[j].Service
+		}
+
+		return states[i].Container < states[j].Container
+	})
+
+	return states, nil
+}
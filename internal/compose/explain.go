@@ -0,0 +1,319 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ExplainedService is a service's merged configuration, with the file that set each field.
+type ExplainedService struct {
+	Name   string
+	Fields []ExplainedField
+}
+
+// ExplainedField is one field of a service's merged configuration.
+//
+// Fields that docker-compose merges by key (like environment and labels) are explained one key
+// at a time, with names like "environment.GOPATH". Fields that docker-compose concatenates
+// (like ports) or merges by container path (volumes and devices) are explained one entry at a
+// time, so several ExplainedFields can have the same Field.
+type ExplainedField struct {
+	Field     string
+	Value     interface{}
+	File      string
+	Generated bool // whether File was generated by docket
+}
+
+// Explain parses each of the files separately and merges them the way docker-compose does,
+// recording which file last set each service field.
+//
+// Values are shown as they appear in the files, before docker-compose interpolates variables.
+func (c Compose) Explain() ([]ExplainedService, error) {
+	return explainFiles(c.Files(), c.generatedFiles)
+}
+
+// ServiceNames returns the names of the services in the docket files for prefix and mode,
+// without running docker-compose.
+func ServiceNames(prefix, mode string) ([]string, error) {
+	files, err := findDocketFiles(prefix, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := mergeFiles(files, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(cfg.services))
+	for i, svc := range cfg.services {
+		names[i] = svc.Name
+	}
+
+	return names, nil
+}
+
+// rawConfig is the part of a compose file that mergeFiles understands. Using yaml.MapSlice
+// keeps each service's fields in the order they were written.
+type rawConfig struct {
+	Services map[string]yaml.MapSlice `yaml:"services"`
+	Networks map[string]interface{}   `yaml:"networks"`
+}
+
+func readRawConfig(file string) (rawConfig, error) {
+	var cfg rawConfig
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	return cfg, nil
+}
+
+// mergedConfig is the result of merging compose files without docker-compose.
+type mergedConfig struct {
+	services     []ExplainedService  // sorted by name
+	serviceFiles map[string][]string // the files that mention each service
+	networks     map[string]string   // the file that last defined each top-level network
+}
+
+func explainFiles(files, generated []string) ([]ExplainedService, error) {
+	cfg, err := mergeFiles(files, generated)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.services, nil
+}
+
+func mergeFiles(files, generated []string) (mergedConfig, error) {
+	isGenerated := map[string]bool{}
+	for _, f := range generated {
+		isGenerated[f] = true
+	}
+
+	services := map[string]*mergedService{}
+	merged := mergedConfig{
+		services:     nil,
+		serviceFiles: map[string][]string{},
+		networks:     map[string]string{},
+	}
+
+	for _, file := range files {
+		cfg, err := readRawConfig(file)
+		if err != nil {
+			return mergedConfig{}, err
+		}
+
+		for name, fields := range cfg.Services {
+			svc, ok := services[name]
+			if !ok {
+				svc = &mergedService{fields: nil, index: map[string]int{}}
+				services[name] = svc
+			}
+			merged.serviceFiles[name] = append(merged.serviceFiles[name], file)
+
+			for _, item := range fields {
+				svc.merge(fmt.Sprint(item.Key), normalizeValue(item.Value), file, isGenerated[file])
+			}
+		}
+
+		for name := range cfg.Networks {
+			merged.networks[name] = file
+		}
+	}
+
+	merged.services = make([]ExplainedService, 0, len(services))
+	for name, svc := range services {
+		merged.services = append(merged.services, ExplainedService{Name: name, Fields: svc.fields})
+	}
+
+	sort.Slice(merged.services, func(i, j int) bool {
+		return merged.services[i].Name < merged.services[j].Name
+	})
+
+	return merged, nil
+}
+
+// How docker-compose merges service fields that aren't simply overridden by later files.
+var (
+	fieldsMergedByKey = map[string]bool{
+		"blkio_config": true, "build": true, "deploy": true, "depends_on": true,
+		"environment": true, "extra_hosts": true, "healthcheck": true, "labels": true,
+		"logging": true, "networks": true, "storage_opt": true, "sysctls": true, "ulimits": true,
+	}
+	fieldsConcatenated = map[string]bool{
+		"cap_add": true, "cap_drop": true, "device_cgroup_rules": true, "dns": true,
+		"dns_opt": true, "dns_search": true, "env_file": true, "expose": true,
+		"external_links": true, "ports": true, "security_opt": true, "tmpfs": true,
+		"volumes_from": true,
+	}
+	fieldsMergedByTarget = map[string]bool{
+		"devices": true, "volumes": true,
+	}
+)
+
+type mergedService struct {
+	fields []ExplainedField
+	index  map[string]int // index into fields by merge key
+}
+
+func (s *mergedService) merge(field string, value interface{}, file string, generated bool) {
+	set := func(key, name string, v interface{}) {
+		f := ExplainedField{Field: name, Value: v, File: file, Generated: generated}
+		if i, ok := s.index[key]; ok {
+			s.fields[i] = f
+
+			return
+		}
+		s.index[key] = len(s.fields)
+		s.fields = append(s.fields, f)
+	}
+
+	switch {
+	case fieldsMergedByKey[field]:
+		m := mappingFromValue(field, value)
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			name := field + "." + k
+			set(name, name, m[k])
+		}
+
+	case fieldsConcatenated[field]:
+		for _, v := range listFromValue(value) {
+			set(fmt.Sprintf("%s\x00%v", field, v), field, v)
+		}
+
+	case fieldsMergedByTarget[field]:
+		for _, v := range listFromValue(value) {
+			set(field+"\x00"+mountTarget(v), field, v)
+		}
+
+	default:
+		set(field, field, value)
+	}
+}
+
+// normalizeValue converts the yaml.MapSlice and map[interface{}]interface{} values that yaml.v2
+// makes into map[string]interface{} values that encoding/json understands.
+func normalizeValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case yaml.MapSlice:
+		m := make(map[string]interface{}, len(v))
+		for _, item := range v {
+			m[fmt.Sprint(item.Key)] = normalizeValue(item.Value)
+		}
+
+		return m
+
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = normalizeValue(val)
+		}
+
+		return m
+
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, val := range v {
+			list[i] = normalizeValue(val)
+		}
+
+		return list
+
+	default:
+		return v
+	}
+}
+
+// mappingFromValue converts the list forms of mapping fields (like `- KEY=value` in
+// environment or `- host:ip` in extra_hosts) into maps.
+func mappingFromValue(field string, value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v
+
+	case []interface{}:
+		sep := "="
+		if field == "extra_hosts" {
+			sep = ":"
+		}
+
+		m := make(map[string]interface{}, len(v))
+		for _, item := range v {
+			s := fmt.Sprint(item)
+			if i := strings.Index(s, sep); i >= 0 {
+				m[s[:i]] = s[i+1:]
+			} else {
+				m[s] = nil
+			}
+		}
+
+		return m
+
+	case string:
+		if field == "build" {
+			return map[string]interface{}{"context": v}
+		}
+
+		return map[string]interface{}{v: nil}
+
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func listFromValue(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case nil:
+		return nil
+	default:
+		return []interface{}{v}
+	}
+}
+
+// mountTarget returns the container path of a volume or device in either the short syntax
+// (SOURCE:TARGET:MODE) or the long syntax.
+func mountTarget(v interface{}) string {
+	if m, ok := v.(map[string]interface{}); ok {
+		return fmt.Sprint(m["target"])
+	}
+
+	parts := strings.Split(fmt.Sprint(v), ":")
+	if len(parts) == 1 {
+		return parts[0]
+	}
+
+	return parts[1]
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func Test_Explain(t *testing.T) {
+	suite.Run(t, new(ExplainSuite))
+}
+
+type ExplainSuite struct {
+	suite.Suite
+
+	dir string
+}
+
+func (s *ExplainSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "docket-explain-test.")
+	s.Require().NoError(err)
+	s.dir = dir
+}
+
+func (s *ExplainSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+func (s *ExplainSuite) writeFile(name, contents string) string {
+	path := filepath.Join(s.dir, name)
+	s.Require().NoError(ioutil.WriteFile(path, []byte(contents), 0600))
+
+	return path
+}
+
+func (s *ExplainSuite) Test_explainFiles() {
+	base := s.writeFile("docket.yaml", `
+version: "3.2"
+services:
+  tester:
+    image: golang:1.13
+    command: ["sleep", "infinity"]
+    environment:
+      - A=1
+      - B=2
+    ports: ["8080"]
+    volumes:
+      - /host/a:/a
+  redis:
+    image: redis
+`)
+	mode := s.writeFile("docket.full.yaml", `
+version: "3.2"
+services:
+  tester:
+    image: golang:1.14
+    environment:
+      B: "3"
+    ports: ["9090"]
+`)
+	mounts := s.writeFile("docket-source-mounts.1.yaml", `
+version: "3.2"
+services:
+  tester:
+    working_dir: /go/src/example.com/a
+    volumes:
+      - type: bind
+        source: /host/b
+        target: /a
+`)
+
+	services, err := explainFiles([]string{base, mode, mounts}, []string{mounts})
+	s.Require().NoError(err)
+	s.Require().Len(services, 2)
+
+	s.Equal(ExplainedService{
+		Name: "redis",
+		Fields: []ExplainedField{
+			{Field: "image", Value: "redis", File: base, Generated: false},
+		},
+	}, services[0])
+
+	s.Equal(ExplainedService{
+		Name: "tester",
+		Fields: []ExplainedField{
+			{Field: "image", Value: "golang:1.14", File: mode, Generated: false},
+			{Field: "command", Value: []interface{}{"sleep", "infinity"}, File: base, Generated: false},
+			{Field: "environment.A", Value: "1", File: base, Generated: false},
+			{Field: "environment.B", Value: "3", File: mode, Generated: false},
+			{Field: "ports", Value: "8080", File: base, Generated: false},
+			{
+				Field: "volumes",
+				Value: map[string]interface{}{"type": "bind", "source": "/host/b", "target": "/a"},
+				File:  mounts, Generated: true,
+			},
+			{Field: "ports", Value: "9090", File: mode, Generated: false},
+			{Field: "working_dir", Value: "/go/src/example.com/a", File: mounts, Generated: true},
+		},
+	}, services[1])
+}
+
+func (s *ExplainSuite) Test_explainFiles_BadFile() {
+	bad := s.writeFile("docket.yaml", "services: [")
+
+	_, err := explainFiles([]string{bad}, nil)
+	s.Error(err)
+}
+
+func (s *ExplainSuite) Test_mappingFromValue() {
+	s.Equal(map[string]interface{}{"A": "1", "B": nil},
+		mappingFromValue("environment", []interface{}{"A=1", "B"}))
+	s.Equal(map[string]interface{}{"db": "10.0.0.1"},
+		mappingFromValue("extra_hosts", []interface{}{"db:10.0.0.1"}))
+	s.Equal(map[string]interface{}{"context": "."}, mappingFromValue("build", "."))
+}
+
+func (s *ExplainSuite) Test_mountTarget() {
+	s.Equal("/a", mountTarget("/host/a:/a:ro"))
+	s.Equal("/a", mountTarget("/a"))
+	s.Equal("/a", mountTarget(map[string]interface{}{"target": "/a"}))
+}
+
+func (s *ExplainSuite) Test_ServiceNames() {
+	oldDir, err := os.Getwd()
+	s.Require().NoError(err)
+	s.Require().NoError(os.Chdir("testdata"))
+	defer func() {
+		s.NoError(os.Chdir(oldDir))
+	}()
+
+	names, err := ServiceNames("docket", "bad-config")
+	s.NoError(err)
+	s.Equal([]string{"alice", "bob"}, names)
+
+	_, err = ServiceNames("docket", "no-such-mode")
+	s.True(errors.Is(err, errNoMatchingDocketFiles), err)
+}
@@ -17,28 +17,98 @@ package compose
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"regexp"
 	"sort"
 )
 
 func findAndSortDocketFiles(prefix, mode string) ([]string, error) {
+	files, err := listCurrentDir()
+	if err != nil {
+		return nil, err
+	}
+
+	fs := newFileSorter(prefix, mode)
+	fs.AddFiles(files)
+
+	return fs.Results(), nil
+}
+
+func listCurrentDir() ([]string, error) {
 	infos, err := ioutil.ReadDir(".")
 	if err != nil {
 		return nil, fmt.Errorf("failed to read current dir: %w", err)
 	}
 
-	files := make([]string, len(infos))
-	for i, info := range infos {
+	return fileNames(infos), nil
+}
+
+// fileNames returns the names of the files (but not directories) in infos.
+func fileNames(infos []os.FileInfo) []string {
+	files := make([]string, 0, len(infos))
+	for _, info := range infos {
 		if info.IsDir() {
 			continue
 		}
-		files[i] = info.Name()
+		files = append(files, info.Name())
 	}
 
-	fs := newFileSorter(prefix, mode)
-	fs.AddFiles(files)
+	return files
+}
 
-	return fs.Results(), nil
+// Mode is a docket mode and the files it uses, in the order docket uses them.
+type Mode struct {
+	Name  string
+	Files []string
+}
+
+// FindModes returns the modes that have files in the current directory starting with prefix.
+func FindModes(prefix string) ([]Mode, error) {
+	files, err := listCurrentDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return findModes(prefix, files), nil
+}
+
+// FindModesInDir returns the modes that have files in dir starting with prefix.
+func FindModesInDir(dir, prefix string) ([]Mode, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Clean(dir), err)
+	}
+
+	return findModes(prefix, fileNames(infos)), nil
+}
+
+// findModes finds the modes that files can be used with.
+//
+// A file named prefix.MODE.yaml or prefix.MODE.*.yaml makes MODE available. (Files named
+// prefix.yaml are used with every mode, but don't make any mode available by themselves.)
+func findModes(prefix string, files []string) []Mode {
+	modePattern := regexp.MustCompile(fmt.Sprintf(`^%s\.([^.]+)\.(.+\.)?ya?ml$`,
+		regexp.QuoteMeta(prefix)))
+
+	names := map[string]bool{}
+	for _, f := range files {
+		if mm := modePattern.FindStringSubmatch(f); mm != nil {
+			names[mm[1]] = true
+		}
+	}
+
+	modes := make([]Mode, 0, len(names))
+	for name := range names {
+		fs := newFileSorter(prefix, name)
+		fs.AddFiles(files)
+
+		modes = append(modes, Mode{Name: name, Files: fs.Results()})
+	}
+
+	sort.Slice(modes, func(i, j int) bool { return modes[i].Name < modes[j].Name })
+
+	return modes
 }
 
 // Ordering:
@@ -89,3 +89,50 @@ func (s *FilesSuite) Test_fileSorter() {
 		s.Equal(c.result, fs.Results(), fmt.Sprintf("prefix=%q mode=%q", c.prefix, c.mode))
 	}
 }
+
+func (s *FilesSuite) Test_findModes() {
+	files := []string{
+		"docket.yaml",
+		"docket.full.yaml",
+		"docket.full.extra.yml",
+		"docket.debug.yaml",
+		"docket.debug.more.yaml",
+		"docket.debug.extra.yaml",
+		"docket.yaml.orig",     // not yaml
+		"docket_other.yaml",    // no dot after prefix
+		"other.full.yaml",      // wrong prefix
+		"docket..yaml",         // empty mode
+		"docket.prefix.x.yaml", // mode "prefix" with extra "x"
+	}
+
+	s.Equal([]Mode{
+		{
+			Name:  "debug",
+			Files: []string{"docket.yaml", "docket.debug.yaml", "docket.debug.extra.yaml", "docket.debug.more.yaml"},
+		},
+		{
+			Name:  "full",
+			Files: []string{"docket.yaml", "docket.full.yaml", "docket.full.extra.yml"},
+		},
+		{
+			Name:  "prefix",
+			Files: []string{"docket.yaml", "docket.prefix.x.yaml"},
+		},
+	}, findModes("docket", files))
+
+	s.Empty(findModes("docket", []string{"docket.yaml"}))
+}
+
+func (s *FilesSuite) Test_FindModesInDir() {
+	modes, err := FindModesInDir("testdata", "docket")
+	s.Require().NoError(err)
+
+	names := make([]string, 0, len(modes))
+	for _, m := range modes {
+		names = append(names, m.Name)
+	}
+	s.Equal([]string{"bad-config", "blank", "published-ports", "test-service"}, names)
+
+	_, err = FindModesInDir("no-such-dir", "docket")
+	s.Error(err)
+}
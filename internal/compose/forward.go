@@ -0,0 +1,218 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/bloomberg/docket/internal/logging"
+)
+
+// netrcTarget is where a forwarded netrc file is mounted inside services.
+const netrcTarget = "/docket-netrc"
+
+// forwardedEnv describes the host environment that should be available inside services.
+type forwardedEnv struct {
+	names []string // variables passed through from the host
+	netrc string   // host netrc file to mount, if any
+}
+
+// parseForwardEnv splits the value of DOCKET_FORWARD_ENV into variable names. Names can be
+// separated by commas or whitespace.
+func parseForwardEnv(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+}
+
+// makeForwardedEnv picks which of the allowed variables to forward from the host.
+//
+// NETRC is special: instead of forwarding the variable, docket mounts the host's netrc file
+// ($NETRC or ~/.netrc) and points NETRC at it.
+func makeForwardedEnv(allowed []string, lookupEnv func(string) (string, bool)) (forwardedEnv, error) {
+	var fwd forwardedEnv
+
+	seen := map[string]bool{}
+	for _, name := range allowed {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		if name == "NETRC" {
+			netrc, err := findNetrc(lookupEnv)
+			if err != nil {
+				return forwardedEnv{}, err
+			}
+			fwd.netrc = netrc
+
+			continue
+		}
+
+		if _, ok := lookupEnv(name); ok {
+			fwd.names = append(fwd.names, name)
+		}
+	}
+
+	sort.Strings(fwd.names)
+
+	return fwd, nil
+}
+
+// findNetrc returns the path of the host's netrc file the same way the go command does, or a
+// blank string if there isn't one.
+func findNetrc(lookupEnv func(string) (string, bool)) (string, error) {
+	if netrc, ok := lookupEnv("NETRC"); ok && netrc != "" {
+		return netrc, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find netrc file: %w", err)
+	}
+
+	base := ".netrc"
+	if runtime.GOOS == "windows" {
+		base = "_netrc"
+	}
+
+	netrc := filepath.Join(home, base)
+	if _, err := os.Stat(netrc); err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("failed to find netrc file: %w", err)
+	}
+
+	return netrc, nil
+}
+
+// addForwardedEnv adds the host variables named in allowList to every service in mountsCfg.
+//
+// The override file only names the variables, so docker-compose passes their values through
+// from its own environment and secrets are never written to disk.
+func addForwardedEnv(
+	mountsCfg *cmpConfig, allowList string, lookupEnv func(string) (string, bool),
+) error {
+	fwd, err := makeForwardedEnv(parseForwardEnv(allowList), lookupEnv)
+	if err != nil {
+		return err
+	}
+
+	if len(fwd.names) == 0 && fwd.netrc == "" {
+		return nil
+	}
+
+	logging.Get().Log(logging.LevelInfo, "forwarding environment",
+		logging.Field{Key: "env", Value: describeForwardedEnv(fwd, lookupEnv)})
+
+	for name, svc := range mountsCfg.Services {
+		var values map[string]string
+		if fwd.netrc != "" {
+			values = map[string]string{"NETRC": netrcTarget}
+
+			volumes := make([]cmpVolume, 0, len(svc.Volumes)+1)
+			volumes = append(volumes, svc.Volumes...)
+			svc.Volumes = append(volumes, cmpVolume{
+				Type:     "bind",
+				Source:   fwd.netrc,
+				Target:   netrcTarget,
+				ReadOnly: true,
+			})
+		}
+
+		svc.Environment = mergeEnvironment(svc.Environment, values)
+		if svc.Environment == nil {
+			svc.Environment = make(map[string]*string, len(fwd.names))
+		}
+		for _, envName := range fwd.names {
+			svc.Environment[envName] = nil
+		}
+
+		mountsCfg.Services[name] = svc
+	}
+
+	return nil
+}
+
+// describeForwardedEnv describes the forwarded environment for traces without revealing
+// secrets.
+func describeForwardedEnv(fwd forwardedEnv, lookupEnv func(string) (string, bool)) []string {
+	descriptions := make([]string, 0, len(fwd.names)+1)
+
+	for _, name := range fwd.names {
+		value, _ := lookupEnv(name)
+		descriptions = append(descriptions, fmt.Sprintf("%s=%s", name, redactEnvValue(name, value)))
+	}
+
+	if fwd.netrc != "" {
+		descriptions = append(descriptions, fmt.Sprintf("NETRC=%s (from %s)", netrcTarget, fwd.netrc))
+	}
+
+	return descriptions
+}
+
+var secretEnvNamePattern = regexp.MustCompile(
+	`(?i)(TOKEN|SECRET|PASSWORD|PASSWD|CREDENTIAL|AUTH|API_?KEY|PRIVATE_KEY)`)
+
+const redacted = "REDACTED"
+
+// redactEnvValue hides values that look secret. Variables with secret-sounding names are hidden
+// entirely, and passwords in URLs (like those in GOPROXY) are replaced.
+func redactEnvValue(name, value string) string {
+	if secretEnvNamePattern.MatchString(name) {
+		return redacted
+	}
+
+	// GOPROXY-style lists are separated by commas or pipes.
+	var sb strings.Builder
+	start := 0
+	for i := 0; i <= len(value); i++ {
+		if i < len(value) && value[i] != ',' && value[i] != '|' {
+			continue
+		}
+
+		sb.WriteString(redactURL(value[start:i]))
+		if i < len(value) {
+			sb.WriteByte(value[i])
+		}
+		start = i + 1
+	}
+
+	return sb.String()
+}
+
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+
+	if _, hasPassword := u.User.Password(); hasPassword {
+		u.User = url.UserPassword(u.User.Username(), redacted)
+	} else {
+		u.User = url.User(redacted)
+	}
+
+	return u.String()
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bloomberg/docket/internal/lease"
+	"github.com/bloomberg/docket/internal/logging"
+	"github.com/bloomberg/docket/internal/statedir"
+)
+
+// generatedFilePrefixes are the prefixes of the files docket generates. Their names continue
+// with the pid of the process that made them, so that sweepGeneratedFiles can tell when
+// they're left over.
+var generatedFilePrefixes = []string{"docket-source-mounts.", "docket-go.", "docket-image-lock."}
+
+// legacyFilePrefixes are the prefixes of the files that older versions of docket generated in
+// the package's directory. Their names don't have a pid, so sweepGeneratedFiles only removes
+// them once they're legacyFileAge old, when no run could still be using them.
+var legacyFilePrefixes = []string{"docket-source-mounts.", "docket-go."}
+
+const legacyFileAge = 24 * time.Hour
+
+// isGeneratedFile reports whether name is the name of a file docket generates. (The legacy
+// files that older versions generated in the package's directory have the same prefixes.)
+func isGeneratedFile(name string) bool {
+	for _, prefix := range generatedFilePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// createGeneratedFile creates a file in docket's state directory (instead of the package's
+// directory, which might be read-only and where leftover files would show up in
+// `git status`). The name is prefix, the pid, and a random string, followed by suffix.
+//
+// It also sweeps up files left over from earlier runs.
+func createGeneratedFile(prefix, suffix string) (*os.File, error) {
+	dir, err := statedir.Dir("generated")
+	if err != nil {
+		return nil, err
+	}
+
+	sweepGeneratedFiles(dir, ".")
+
+	return ioutil.TempFile(dir, fmt.Sprintf("%s%d.*%s", prefix, os.Getpid(), suffix))
+}
+
+// removeGeneratedFile removes a generated file unless DOCKET_KEEP_MOUNTS_FILE is set.
+func removeGeneratedFile(path string) error {
+	if os.Getenv("DOCKET_KEEP_MOUNTS_FILE") != "" {
+		logging.Get().Log(logging.LevelInfo, "leaving generated file alone", logging.File(path))
+
+		return nil
+	}
+
+	return os.Remove(path)
+}
+
+// writeFileIfChanged writes contents to a file that's kept at a stable path (rather than
+// generated and removed for each run) unless the file already has those contents.
+func writeFileIfChanged(path string, contents []byte) error {
+	if existing, err := ioutil.ReadFile(path); err == nil && bytes.Equal(existing, contents) {
+		return nil
+	}
+
+	// Writing a temporary file and renaming it means that no one sees a partial file.
+	tempFile, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = tempFile.Write(contents)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tempFile.Name(), 0644) //nolint:gosec // not secret
+	}
+	if err == nil {
+		err = os.Rename(tempFile.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tempFile.Name())
+	}
+
+	return err
+}
+
+// sweepGeneratedFiles removes the generated files in stateDir whose processes have exited and
+// the files that older versions of docket left in pkgDir, once they're legacyFileAge old. It
+// leaves everything alone if DOCKET_KEEP_MOUNTS_FILE is set, since those files were kept on
+// purpose.
+//
+// Sweeping is best effort, so it ignores errors.
+func sweepGeneratedFiles(stateDir, pkgDir string) {
+	if os.Getenv("DOCKET_KEEP_MOUNTS_FILE") != "" {
+		return
+	}
+
+	for _, f := range findGeneratedFiles(stateDir, generatedFilePrefixes) {
+		if pid, ok := generatedFilePid(f); ok && !lease.ProcessAlive(pid) {
+			logging.Get().Log(logging.LevelInfo, "removing leftover generated file",
+				logging.File(filepath.Join(stateDir, f)))
+			_ = os.Remove(filepath.Join(stateDir, f))
+		}
+	}
+
+	for _, f := range findGeneratedFiles(pkgDir, legacyFilePrefixes) {
+		path := filepath.Join(pkgDir, f)
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) >= legacyFileAge {
+			logging.Get().Log(logging.LevelInfo, "removing leftover generated file",
+				logging.File(path))
+			_ = os.Remove(path)
+		}
+	}
+}
+
+func findGeneratedFiles(dir string, prefixes []string) []string {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, f := range fileNames(infos) {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(f, prefix) {
+				files = append(files, f)
+
+				break
+			}
+		}
+	}
+
+	return files
+}
+
+// generatedFilePid returns the pid in a generated file's name. Files generated by older versions
+// of docket don't have one.
+func generatedFilePid(name string) (int, bool) {
+	parts := strings.Split(name, ".")
+	if len(parts) < 4 {
+		return 0, false
+	}
+
+	pid, err := strconv.Atoi(parts[1])
+
+	return pid, err == nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func Test_Generated(t *testing.T) {
+	suite.Run(t, new(GeneratedSuite))
+}
+
+type GeneratedSuite struct {
+	suite.Suite
+
+	dir string
+}
+
+func (s *GeneratedSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "docket-generated-test.")
+	s.Require().NoError(err)
+	s.dir = dir
+}
+
+func (s *GeneratedSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+func (s *GeneratedSuite) touch(dir, name string) string {
+	s.Require().NoError(os.MkdirAll(dir, 0700))
+
+	path := filepath.Join(dir, name)
+	s.Require().NoError(ioutil.WriteFile(path, nil, 0600))
+
+	return path
+}
+
+func (s *GeneratedSuite) exists(path string) bool {
+	_, err := os.Stat(path)
+
+	return err == nil
+}
+
+func (s *GeneratedSuite) Test_createGeneratedFile() {
+	s.Require().NoError(os.Setenv("DOCKET_STATE_DIR", s.dir))
+	defer os.Unsetenv("DOCKET_STATE_DIR")
+
+	f, err := createGeneratedFile("docket-source-mounts.", ".yaml")
+	s.Require().NoError(err)
+	s.NoError(f.Close())
+
+	s.Equal(filepath.Join(s.dir, "generated"), filepath.Dir(f.Name()))
+
+	pid, ok := generatedFilePid(filepath.Base(f.Name()))
+	s.True(ok)
+	s.Equal(os.Getpid(), pid)
+
+	s.NoError(removeGeneratedFile(f.Name()))
+	s.False(s.exists(f.Name()))
+}
+
+func (s *GeneratedSuite) Test_sweepGeneratedFiles() {
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	s.Require().NoError(cmd.Run())
+	deadPid := cmd.ProcessState.Pid()
+
+	stateDir := filepath.Join(s.dir, "state")
+	pkgDir := filepath.Join(s.dir, "pkg")
+
+	dead := s.touch(stateDir, fmt.Sprintf("docket-source-mounts.%d.123.yaml", deadPid))
+	live := s.touch(stateDir, fmt.Sprintf("docket-go.%d.456.work", os.Getpid()))
+	legacyMounts := s.touch(pkgDir, "docket-source-mounts.789.yaml")
+	legacyGoWork := s.touch(pkgDir, "docket-go.789.work")
+	recentMounts := s.touch(pkgDir, "docket-source-mounts.790.yaml")
+	imageLock := s.touch(pkgDir, "docket-image-lock.791.yaml")
+	docketFile := s.touch(pkgDir, "docket.yaml")
+
+	old := time.Now().Add(-legacyFileAge - time.Minute)
+	for _, f := range []string{legacyMounts, legacyGoWork, imageLock} {
+		s.Require().NoError(os.Chtimes(f, old, old))
+	}
+
+	s.Require().NoError(os.Setenv("DOCKET_KEEP_MOUNTS_FILE", "1"))
+	sweepGeneratedFiles(stateDir, pkgDir)
+	s.Require().NoError(os.Unsetenv("DOCKET_KEEP_MOUNTS_FILE"))
+
+	s.True(s.exists(dead), "kept files shouldn't be swept")
+	s.True(s.exists(legacyMounts), "kept files shouldn't be swept")
+
+	sweepGeneratedFiles(stateDir, pkgDir)
+
+	s.False(s.exists(dead))
+	s.True(s.exists(live))
+	s.False(s.exists(legacyMounts))
+	s.False(s.exists(legacyGoWork))
+	s.True(s.exists(recentMounts), "recent files might still be in use")
+	s.True(s.exists(imageLock), "docket never generated image lock files in pkgDir")
+	s.True(s.exists(docketFile))
+}
+
+func (s *GeneratedSuite) Test_generatedFilePid() {
+	pid, ok := generatedFilePid("docket-source-mounts.42.123.yaml")
+	s.True(ok)
+	s.Equal(42, pid)
+
+	_, ok = generatedFilePid("docket-source-mounts.123.yaml")
+	s.False(ok)
+}
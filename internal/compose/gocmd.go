@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
@@ -39,6 +40,124 @@ func runGoEnvGOPATH(ctx context.Context) ([]string, error) {
 	return filepath.SplitList(strings.TrimSpace(string(out))), nil
 }
 
+// runGoEnvGOWORK returns the path to the active go.work file, or a blank string if there isn't
+// one. Versions of Go that don't support workspaces print a blank line.
+func runGoEnvGOWORK(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, "go", "env", "GOWORK")
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("failed go env GOWORK: %w: %s", exitErr, exitErr.Stderr)
+		}
+
+		return "", fmt.Errorf("failed 'go env GOWORK': %w", err)
+	}
+
+	goWork := strings.TrimSpace(string(out))
+	if goWork == "off" {
+		return "", nil
+	}
+
+	return goWork, nil
+}
+
+// GoDirective returns the Go version from the current module's go directive, or a blank
+// string outside of a module.
+func GoDirective(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, "go", "env", "GOMOD")
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("failed go env GOMOD: %w: %s", exitErr, exitErr.Stderr)
+		}
+
+		return "", fmt.Errorf("failed 'go env GOMOD': %w", err)
+	}
+
+	goMod := strings.TrimSpace(string(out))
+	if goMod == "" || goMod == os.DevNull {
+		return "", nil
+	}
+
+	gm, err := runGoModEditJSON(ctx, goMod)
+	if err != nil {
+		return "", err
+	}
+
+	return gm.Go, nil
+}
+
+type goModVersion struct {
+	Path    string
+	Version string
+}
+
+type goReplace struct {
+	Old goModVersion
+	New goModVersion
+}
+
+// isLocal reports whether the replacement is a directory rather than a module version.
+func (r goReplace) isLocal() bool {
+	return r.New.Version == ""
+}
+
+// goModFile is the output of `go mod edit -json`.
+type goModFile struct {
+	Go      string
+	Replace []goReplace
+}
+
+// goWorkFile is the output of `go work edit -json`.
+type goWorkFile struct {
+	Go  string
+	Use []struct {
+		DiskPath string
+	}
+	Replace []goReplace
+}
+
+func runGoModEditJSON(ctx context.Context, goModPath string) (goModFile, error) {
+	var gm goModFile
+	if err := runGoEditJSON(ctx, "mod", goModPath, &gm); err != nil {
+		return goModFile{}, err
+	}
+
+	return gm, nil
+}
+
+func runGoWorkEditJSON(ctx context.Context, goWorkPath string) (goWorkFile, error) {
+	var gw goWorkFile
+	if err := runGoEditJSON(ctx, "work", goWorkPath, &gw); err != nil {
+		return goWorkFile{}, err
+	}
+
+	return gw, nil
+}
+
+// runGoEditJSON runs `go mod edit -json` or `go work edit -json` on a file.
+func runGoEditJSON(ctx context.Context, subcommand, path string, v interface{}) error {
+	cmd := exec.CommandContext(ctx, "go", subcommand, "edit", "-json", path)
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("failed go %s edit -json %s: %w: %s",
+				subcommand, path, exitErr, exitErr.Stderr)
+		}
+
+		return fmt.Errorf("failed go %s edit -json %s: %w", subcommand, path, err)
+	}
+
+	if err := json.Unmarshal(out, v); err != nil {
+		return fmt.Errorf("failed json.Unmarshal: %w", err)
+	}
+
+	return nil
+}
+
 type goList struct {
 	Dir        string
 	ImportPath string
@@ -0,0 +1,184 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	// healthLogLines is how many lines of each unhealthy service's logs an UnhealthyError
+	// includes.
+	healthLogLines = 20
+
+	healthPollInterval = 500 * time.Millisecond
+)
+
+// UnhealthyError reports services that weren't running properly after `docker-compose up`.
+type UnhealthyError struct {
+	States   []ContainerState  // every container, for the status table
+	Problems map[string]string // container name -> what's wrong
+	Logs     map[string]string // service -> the last lines of its logs
+}
+
+func (e *UnhealthyError) Error() string {
+	var b strings.Builder
+
+	services := e.unhealthyServices()
+	fmt.Fprintf(&b, "unhealthy services after docker-compose up: %s\n\n",
+		strings.Join(services, ", "))
+
+	var table strings.Builder
+	tw := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "SERVICE\tCONTAINER\tSTATUS\tPROBLEM\n")
+	for _, s := range e.States {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			s.Service, s.Container, describeContainerState(s), e.Problems[s.Container])
+	}
+	tw.Flush()
+
+	for _, line := range strings.Split(strings.TrimSuffix(table.String(), "\n"), "\n") {
+		fmt.Fprintln(&b, strings.TrimRight(line, " "))
+	}
+
+	for _, service := range services {
+		if logs := strings.TrimRight(e.Logs[service], "\n"); logs != "" {
+			fmt.Fprintf(&b, "\nlast log lines of %s:\n%s\n", service, logs)
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (e *UnhealthyError) unhealthyServices() []string {
+	var services []string
+
+	seen := map[string]bool{}
+	for _, s := range e.States {
+		if _, ok := e.Problems[s.Container]; ok && !seen[s.Service] {
+			services = append(services, s.Service)
+			seen[s.Service] = true
+		}
+	}
+
+	return services
+}
+
+// describeContainerState describes a container's state for the status table.
+func describeContainerState(s ContainerState) string {
+	switch {
+	case s.Status == "exited":
+		return fmt.Sprintf("exited (%d)", s.ExitCode)
+	case s.Status == "restarting":
+		return fmt.Sprintf("restarting (%d restarts)", s.RestartCount)
+	case s.Health != "":
+		return fmt.Sprintf("%s (%s)", s.Status, s.Health)
+	}
+
+	return s.Status
+}
+
+// containerProblem returns what's wrong with a container, or "" if it's fine. A container that
+// exited with code 0 is fine, since it may be a one-shot job that finished before the check. A
+// container whose healthcheck is still starting is fine until the caller stops waiting for it.
+func containerProblem(s ContainerState, stillWaiting bool) string {
+	switch {
+	case s.Status == "exited" && s.ExitCode == 0:
+		return ""
+	case s.Status == "exited" && s.ExpectExit:
+		return "failed"
+	case s.Status == "exited":
+		return "exited"
+	case s.ExpectExit && s.Status == "running":
+		return ""
+	case s.Status != "running":
+		return s.Status
+	case s.Health == "unhealthy":
+		return "unhealthy"
+	case s.Health == "starting" && !stillWaiting:
+		return "healthcheck timed out"
+	}
+
+	return ""
+}
+
+// checkContainers returns the problems with states and whether any healthcheck is starting.
+func checkContainers(states []ContainerState, stillWaiting bool) (map[string]string, bool) {
+	problems := map[string]string{}
+	starting := false
+
+	for _, s := range states {
+		if problem := containerProblem(s, stillWaiting); problem != "" {
+			problems[s.Container] = problem
+		}
+		if s.Health == "starting" {
+			starting = true
+		}
+	}
+
+	return problems, starting
+}
+
+// CheckHealth checks that every service is running or has exited successfully after
+// `docker-compose up`. It waits up to timeout for services with healthchecks to become healthy.
+// If a service isn't healthy, it returns an *UnhealthyError.
+func (c Compose) CheckHealth(ctx context.Context, timeout time.Duration) error {
+	defer c.logStep("health check")()
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		states, err := c.ContainerStates(ctx)
+		if err != nil {
+			return err
+		}
+
+		stillWaiting := time.Now().Before(deadline)
+
+		problems, starting := checkContainers(states, stillWaiting)
+		if len(problems) > 0 {
+			return c.unhealthyError(ctx, states, problems)
+		}
+		if !starting {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(healthPollInterval):
+		}
+	}
+}
+
+func (c Compose) unhealthyError(
+	ctx context.Context, states []ContainerState, problems map[string]string,
+) *UnhealthyError {
+	e := &UnhealthyError{States: states, Problems: problems, Logs: map[string]string{}}
+
+	for _, service := range e.unhealthyServices() {
+		logs, err := c.Logs(ctx, healthLogLines, service)
+		if err != nil {
+			logs = []byte(fmt.Sprintf("(failed to get logs: %v)", err))
+		}
+		e.Logs[service] = string(logs)
+	}
+
+	return e
+}
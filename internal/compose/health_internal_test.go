@@ -0,0 +1,101 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func Test_Health(t *testing.T) {
+	suite.Run(t, new(HealthSuite))
+}
+
+type HealthSuite struct {
+	suite.Suite
+}
+
+func withHealth(s ContainerState, health string) ContainerState {
+	s.Health = health
+
+	return s
+}
+
+func (s *HealthSuite) Test_containerProblem() {
+	for _, tc := range []struct {
+		state        ContainerState
+		stillWaiting bool
+		problem      string
+	}{
+		{state("redis", "running", 0, 0, false), false, ""},
+		{state("redis", "exited", 1, 0, false), true, "exited"},
+		{state("redis", "exited", 0, 0, false), true, ""},
+		{state("redis", "restarting", 1, 3, false), true, "restarting"},
+		{state("redis", "dead", 0, 0, false), true, "dead"},
+		{withHealth(state("redis", "running", 0, 0, false), "healthy"), false, ""},
+		{withHealth(state("redis", "running", 0, 0, false), "unhealthy"), true, "unhealthy"},
+		{withHealth(state("redis", "running", 0, 0, false), "starting"), true, ""},
+		{
+			withHealth(state("redis", "running", 0, 0, false), "starting"), false,
+			"healthcheck timed out",
+		},
+		{state("migrate", "running", 0, 0, true), false, ""},
+		{state("migrate", "exited", 0, 0, true), false, ""},
+		{state("migrate", "exited", 2, 0, true), false, "failed"},
+	} {
+		s.Equal(tc.problem, containerProblem(tc.state, tc.stillWaiting), "%+v", tc.state)
+	}
+}
+
+func (s *HealthSuite) Test_checkContainers() {
+	problems, starting := checkContainers([]ContainerState{
+		state("redis", "running", 0, 0, false),
+		withHealth(state("db", "running", 0, 0, false), "starting"),
+	}, true)
+	s.Empty(problems)
+	s.True(starting)
+
+	problems, starting = checkContainers([]ContainerState{
+		state("redis", "exited", 1, 0, false),
+	}, true)
+	s.Equal(map[string]string{"app_redis_1": "exited"}, problems)
+	s.False(starting)
+}
+
+func (s *HealthSuite) Test_UnhealthyError() {
+	err := &UnhealthyError{
+		States: []ContainerState{
+			withHealth(state("db", "running", 0, 0, false), "healthy"),
+			state("redis", "exited", 1, 0, false),
+			state("web", "restarting", 1, 3, false),
+		},
+		Problems: map[string]string{"app_redis_1": "exited", "app_web_1": "restarting"},
+		Logs: map[string]string{
+			"redis": "redis_1  | bad config\n",
+			"web":   "",
+		},
+	}
+
+	s.Equal(`unhealthy services after docker-compose up: redis, web
+
+SERVICE  CONTAINER    STATUS                   PROBLEM
+db       app_db_1     running (healthy)
+redis    app_redis_1  exited (1)               exited
+web      app_web_1    restarting (3 restarts)  restarting
+
+last log lines of redis:
+redis_1  | bad config`, err.Error())
+}
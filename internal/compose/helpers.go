@@ -77,3 +77,73 @@ func makeRunArgForTest(testName, runArg string) string {
 
 	return strings.Join(testParts, "/")
 }
+
+// makeGoTestArgs makes the command line that runs `go test` inside the test service.
+//
+// runArg is passed as -run if it isn't empty, and testArgs are passed through unchanged.
+func makeGoTestArgs(runArg string, testArgs []string) []string {
+	args := []string{"go", "test"}
+
+	if runArg != "" {
+		args = append(args, "-run", runArg)
+	}
+
+	return append(args, testArgs...)
+}
+
+// makeTestBinaryArgs makes the command line that runs a test binary inside the test service.
+//
+// Test binaries don't understand `go test`'s shorthand flags, so we use the -test.* forms.
+func makeTestBinaryArgs(binaryPath, runArg string, testArgs []string) []string {
+	args := []string{binaryPath}
+
+	if runArg != "" {
+		args = append(args, "-test.run", runArg)
+	}
+
+	return append(args, makeTestBinaryFlags(testArgs)...)
+}
+
+// testBinaryFlags are the `go test` flags that a test binary accepts as -test.<flag>.
+var testBinaryFlags = map[string]bool{
+	"bench": true, "benchmem": true, "benchtime": true, "blockprofile": true,
+	"blockprofilerate": true, "count": true, "coverprofile": true, "cpu": true,
+	"cpuprofile": true, "failfast": true, "fullpath": true, "fuzz": true,
+	"fuzzminimizetime": true, "fuzztime": true, "list": true, "memprofile": true,
+	"memprofilerate": true, "mutexprofile": true, "mutexprofilefraction": true,
+	"outputdir": true, "parallel": true, "run": true, "short": true, "shuffle": true,
+	"skip": true, "timeout": true, "trace": true, "v": true,
+}
+
+// makeTestBinaryFlags rewrites `go test` flags like -v and -count=1 into the -test.v and
+// -test.count=1 forms that test binaries understand. Other arguments are left alone, and
+// everything after -args or -- is passed through unchanged.
+func makeTestBinaryFlags(testArgs []string) []string {
+	flags := make([]string, 0, len(testArgs))
+
+	for i, arg := range testArgs {
+		if arg == "-args" || arg == "--args" || arg == "--" {
+			return append(flags, testArgs[i+1:]...)
+		}
+
+		if !strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+
+			continue
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		value := ""
+		if eq := strings.Index(name, "="); eq >= 0 {
+			name, value = name[:eq], name[eq:]
+		}
+
+		if testBinaryFlags[name] {
+			arg = "-test." + name + value
+		}
+
+		flags = append(flags, arg)
+	}
+
+	return flags
+}
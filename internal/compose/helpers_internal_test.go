@@ -85,3 +85,47 @@ func (s *HelpersSuite) Test_makeRunArgForTest() {
 
 	s.Panics(func() { makeRunArgForTest("", "runArg") })
 }
+
+func (s *HelpersSuite) Test_makeGoTestArgs() {
+	s.Equal([]string{"go", "test", "-run", "^top$"}, makeGoTestArgs("^top$", nil))
+	s.Equal([]string{"go", "test", "-run", "^top$", "-v"},
+		makeGoTestArgs("^top$", []string{"-v"}))
+	s.Equal([]string{"go", "test", "-count=1"}, makeGoTestArgs("", []string{"-count=1"}))
+}
+
+func (s *HelpersSuite) Test_makeTestBinaryArgs() {
+	s.Equal([]string{"/bin/x.test", "-test.run", "^top$/sub"},
+		makeTestBinaryArgs("/bin/x.test", "^top$/sub", nil))
+	s.Equal([]string{"/bin/x.test", "-test.run", "^top$", "-test.v"},
+		makeTestBinaryArgs("/bin/x.test", "^top$", []string{"-v"}))
+	s.Equal([]string{"/bin/x.test"}, makeTestBinaryArgs("/bin/x.test", "", nil))
+}
+
+func (s *HelpersSuite) Test_makeTestBinaryFlags() {
+	s.Equal([]string{}, makeTestBinaryFlags(nil))
+	s.Equal(
+		[]string{"-test.v", "-test.count=1", "-test.timeout", "30s", "-test.short", "-race"},
+		makeTestBinaryFlags([]string{"-v", "-count=1", "-timeout", "30s", "--short", "-race"}))
+	s.Equal([]string{"-test.v", "-v", "extra"},
+		makeTestBinaryFlags([]string{"--v", "-args", "-v", "extra"}))
+}
+
+func (s *HelpersSuite) Test_normalizeProjectName() {
+	s.Equal("03_redispinger-service", normalizeProjectName("03_redispinger-service"))
+	s.Equal("myproject", normalizeProjectName("My Project!"))
+}
+
+func (s *HelpersSuite) Test_SourceMountServices() {
+	label := func(value string) map[string]string {
+		return map[string]string{"com.bloomberg.docket": value}
+	}
+
+	cmp := Compose{cfg: cmpConfig{Services: map[string]cmpService{
+		"tester":  {Labels: label("run go test")},
+		"server":  {Labels: label("mount go sources")},
+		"another": {Labels: label("mount go sources")},
+		"redis":   {Labels: nil},
+	}}}
+
+	s.Equal([]string{"another", "server"}, cmp.SourceMountServices())
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+
+	"github.com/bloomberg/docket/internal/logging"
+)
+
+// ServiceImage is the image a service runs.
+type ServiceImage struct {
+	Service string
+	Image   string
+}
+
+func (si ServiceImage) String() string {
+	return fmt.Sprintf("%s (%s)", si.Service, si.Image)
+}
+
+// PulledImages returns the images that `docker-compose pull` pulls: the images of the services
+// that don't build their own. They're sorted by service.
+func (c Compose) PulledImages() []ServiceImage {
+	var images []ServiceImage
+
+	for name, svc := range c.cfg.Services {
+		if svc.Image == "" || svc.Build != nil {
+			continue
+		}
+
+		images = append(images, ServiceImage{Service: name, Image: svc.Image})
+	}
+
+	sort.Slice(images, func(i, j int) bool { return images[i].Service < images[j].Service })
+
+	return images
+}
+
+// MissingImages runs `docker image inspect` to find which of the PulledImages aren't present
+// locally.
+func (c Compose) MissingImages(ctx context.Context) ([]ServiceImage, error) {
+	var missing []ServiceImage
+
+	for _, si := range c.PulledImages() {
+		cmd := exec.CommandContext(ctx, "docker", "image", "inspect", "--format", "{{.Id}}", si.Image)
+
+		c.log(logging.LevelDebug, "image inspect", logging.Service(si.Service),
+			logging.Command(cmd.Args))
+
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+
+		if err := cmd.Run(); err != nil {
+			if !isNoSuchImage(stderr.Bytes()) {
+				return nil, fmt.Errorf("failed docker image inspect %s: %w: %s",
+					si.Image, err, stderr.Bytes())
+			}
+
+			missing = append(missing, si)
+		}
+	}
+
+	return missing, nil
+}
+
+// isNoSuchImage returns whether `docker image inspect` failed because the image isn't present.
+func isNoSuchImage(stderr []byte) bool {
+	return bytes.Contains(bytes.ToLower(stderr), []byte("no such image"))
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func Test_Images(t *testing.T) {
+	suite.Run(t, new(ImagesSuite))
+}
+
+type ImagesSuite struct {
+	suite.Suite
+}
+
+func (s *ImagesSuite) Test_PulledImages() {
+	c := Compose{cfg: cmpConfig{Services: map[string]cmpService{
+		"redis":  {Image: "redis:6"},
+		"app":    {Image: "example/app", Build: "."},
+		"built":  {Build: map[interface{}]interface{}{"context": "."}},
+		"tester": {Image: "golang:1.16"},
+	}}}
+
+	s.Equal([]ServiceImage{
+		{Service: "redis", Image: "redis:6"},
+		{Service: "tester", Image: "golang:1.16"},
+	}, c.PulledImages())
+}
+
+func (s *ImagesSuite) Test_isNoSuchImage() {
+	s.True(isNoSuchImage([]byte("Error: No such image: redis:6\n")))
+	s.True(isNoSuchImage([]byte("Error response from daemon: no such image: redis:6: " +
+		"image not known\n")))
+	s.False(isNoSuchImage([]byte("Cannot connect to the Docker daemon\n")))
+}
@@ -0,0 +1,277 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Severities of LintProblems.
+const (
+	LintError   = "error"   // docket can't run tests in the mode
+	LintWarning = "warning" // docket can run tests, but probably not the way you meant
+)
+
+// LintProblem is a problem that Lint found in docket files.
+type LintProblem struct {
+	Severity string
+	Modes    []string // the modes with the problem (empty if it isn't about a mode)
+	File     string
+	Service  string // empty if the problem isn't about a service
+	Message  string
+}
+
+// Lint checks the docket files in the current directory for every mode with prefix, without
+// running docker-compose.
+func Lint(prefix string) ([]LintProblem, error) {
+	files, err := listCurrentDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return lintFiles(prefix, files), nil
+}
+
+func lintFiles(prefix string, files []string) []LintProblem {
+	var problems lintProblems
+
+	lintUnusedFiles(&problems, prefix, files)
+
+	unreadable := map[string]bool{}
+	for _, mode := range findModes(prefix, files) {
+		ok := true
+		for _, f := range mode.Files {
+			if unreadable[f] {
+				ok = false
+
+				continue
+			}
+			if _, err := readRawConfig(f); err != nil {
+				unreadable[f] = true
+				ok = false
+				problems.add(LintProblem{
+					Severity: LintError, Modes: nil, File: f, Service: "", Message: err.Error(),
+				})
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		cfg, err := mergeFiles(mode.Files, nil)
+		if err != nil {
+			problems.add(LintProblem{
+				Severity: LintError, Modes: []string{mode.Name}, File: "", Service: "",
+				Message: err.Error(),
+			})
+
+			continue
+		}
+
+		lintConfig(&problems, mode.Name, cfg)
+	}
+
+	return problems.sorted()
+}
+
+// lintUnusedFiles finds files that look like docket files but that no mode uses.
+func lintUnusedFiles(problems *lintProblems, prefix string, files []string) {
+	used := regexp.MustCompile(fmt.Sprintf(`^%[1]s\.ya?ml$|^%[1]s\.[^.]+\.(.+\.)?ya?ml$`,
+		regexp.QuoteMeta(prefix)))
+
+	for _, f := range files {
+		if isGeneratedFile(f) {
+			continue
+		}
+
+		if strings.HasPrefix(f, prefix+".") && !used.MatchString(f) && f != LockFile(prefix) {
+			problems.add(LintProblem{
+				Severity: LintWarning,
+				Modes:    nil,
+				File:     f,
+				Service:  "",
+				Message: fmt.Sprintf("no mode uses this file (docket uses %[1]s.yaml, "+
+					"%[1]s.MODE.yaml, and %[1]s.MODE.*.yaml)", prefix),
+			})
+		}
+	}
+}
+
+func lintConfig(problems *lintProblems, mode string, cfg mergedConfig) {
+	var testServices []lintService
+
+	for _, explained := range cfg.services {
+		svc := newLintService(explained, cfg.serviceFiles[explained.Name])
+		report := func(severity, file, format string, args ...interface{}) {
+			problems.add(LintProblem{
+				Severity: severity,
+				Modes:    []string{mode},
+				File:     file,
+				Service:  svc.name,
+				Message:  fmt.Sprintf(format, args...),
+			})
+		}
+
+		label, err := parseDocketLabelValue(svc.label.value)
+		if err != nil {
+			report(LintError, svc.label.file, "%v", err)
+		} else if label.runGoTest {
+			testServices = append(testServices, svc)
+
+			if len(svc.command) > 0 && oneShotCommands[path.Base(svc.command[0])] {
+				report(LintWarning, svc.label.file,
+					"the test service's command (%s) exits right away, so docket can't run "+
+						"the tests in it (try `command: sleep infinity`)",
+					strings.Join(svc.command, " "))
+			}
+		}
+
+		if _, err := parseExpectExitLabelValue(svc.expectExit.value); err != nil {
+			report(LintError, svc.expectExit.file, "%v", err)
+		}
+
+		if !svc.hasImage {
+			report(LintError, svc.files[len(svc.files)-1], "the service has no image or build")
+		}
+
+		for _, network := range svc.networks {
+			if _, ok := cfg.networks[network.value]; !ok && network.value != "default" {
+				report(LintError, network.file, "network %q isn't defined", network.value)
+			}
+		}
+	}
+
+	if len(testServices) > 1 {
+		names := make([]string, len(testServices))
+		for i, svc := range testServices {
+			names[i] = svc.name
+		}
+
+		for _, svc := range testServices {
+			problems.add(LintProblem{
+				Severity: LintError,
+				Modes:    []string{mode},
+				File:     svc.label.file,
+				Service:  svc.name,
+				Message: fmt.Sprintf("%v (%s)", errMultipleTestServices,
+					strings.Join(names, ", ")),
+			})
+		}
+	}
+}
+
+// lintService is the part of a merged service that lintConfig checks.
+type lintService struct {
+	name       string
+	files      []string
+	label      lintValue
+	expectExit lintValue
+	command    []string
+	hasImage   bool
+	networks   []lintValue
+}
+
+// oneShotCommands are commands that exit right away. Whether other commands keep running
+// depends on the image, so lintConfig only warns about a test service's command if it's one
+// of these.
+var oneShotCommands = map[string]bool{"true": true, "false": true, "echo": true, "exit": true}
+
+// commandWords splits a service's command, which can be a string or a list.
+func commandWords(command interface{}) []string {
+	list, ok := command.([]interface{})
+	if !ok {
+		return strings.Fields(fmt.Sprint(command))
+	}
+
+	words := make([]string, 0, len(list))
+	for _, word := range list {
+		words = append(words, fmt.Sprint(word))
+	}
+
+	return words
+}
+
+type lintValue struct {
+	value string
+	file  string
+}
+
+func newLintService(explained ExplainedService, files []string) lintService {
+	svc := lintService{
+		name:       explained.Name,
+		files:      files,
+		label:      lintValue{value: "", file: ""},
+		expectExit: lintValue{value: "", file: ""},
+		command:    nil,
+		hasImage:   false,
+		networks:   nil,
+	}
+
+	for _, f := range explained.Fields {
+		switch {
+		case f.Field == "labels."+docketLabelKey:
+			svc.label = lintValue{value: fmt.Sprint(f.Value), file: f.File}
+		case f.Field == "labels."+expectExitLabelKey:
+			svc.expectExit = lintValue{value: fmt.Sprint(f.Value), file: f.File}
+		case f.Field == "command" && f.Value != nil:
+			svc.command = commandWords(f.Value)
+		case f.Field == "image" || strings.HasPrefix(f.Field, "build."):
+			svc.hasImage = true
+		case strings.HasPrefix(f.Field, "networks."):
+			svc.networks = append(svc.networks,
+				lintValue{value: strings.TrimPrefix(f.Field, "networks."), file: f.File})
+		}
+	}
+
+	return svc
+}
+
+// lintProblems collects LintProblems, combining the same problem found in several modes.
+type lintProblems struct {
+	problems []LintProblem
+}
+
+func (lp *lintProblems) add(p LintProblem) {
+	for i := range lp.problems {
+		q := &lp.problems[i]
+		if q.Severity == p.Severity && q.File == p.File && q.Service == p.Service &&
+			q.Message == p.Message {
+			q.Modes = append(q.Modes, p.Modes...)
+
+			return
+		}
+	}
+
+	lp.problems = append(lp.problems, p)
+}
+
+func (lp *lintProblems) sorted() []LintProblem {
+	problems := append([]LintProblem{}, lp.problems...)
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		a, b := problems[i], problems[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+
+		return a.Service < b.Service
+	})
+
+	return problems
+}
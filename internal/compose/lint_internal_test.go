@@ -0,0 +1,178 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func Test_Lint(t *testing.T) {
+	suite.Run(t, new(LintSuite))
+}
+
+type LintSuite struct {
+	suite.Suite
+
+	oldDir string
+	dir    string
+}
+
+func (s *LintSuite) SetupTest() {
+	oldDir, err := os.Getwd()
+	s.Require().NoError(err)
+	s.oldDir = oldDir
+
+	dir, err := ioutil.TempDir("", "docket-lint-test.")
+	s.Require().NoError(err)
+	s.dir = dir
+
+	s.Require().NoError(os.Chdir(dir))
+}
+
+func (s *LintSuite) TearDownTest() {
+	s.NoError(os.Chdir(s.oldDir))
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+func (s *LintSuite) writeFiles(files map[string]string) {
+	for name, contents := range files {
+		s.Require().NoError(ioutil.WriteFile(name, []byte(contents), 0600))
+	}
+}
+
+func (s *LintSuite) Test_Clean() {
+	s.writeFiles(map[string]string{
+		"docket.yaml": `
+version: "3.2"
+services:
+  tester:
+    image: golang:1.13
+    command: sleep infinity
+    labels:
+      com.bloomberg.docket: run go test
+    networks: [default, backend]
+networks:
+  backend: {}
+`,
+		"docket.full.yaml": `
+version: "3.2"
+services:
+  redis:
+    build: ./redis
+`,
+		"docket.lock": "images: {}\n",
+	})
+
+	problems, err := Lint("docket")
+	s.Require().NoError(err)
+	s.Empty(problems)
+}
+
+func (s *LintSuite) Test_Problems() {
+	s.writeFiles(map[string]string{
+		"docket.yaml": `
+version: "3.2"
+services:
+  tester:
+    image: golang:1.13
+    command: ["true"]
+    labels:
+      com.bloomberg.docket: run go test
+`,
+		"docket.full.yaml": `
+version: "3.2"
+services:
+  other:
+    image: golang:1.13
+    command: sleep infinity
+    labels:
+      - com.bloomberg.docket=run go test
+  redis:
+    networks: [backend]
+`,
+		"docket.debug.yaml": `
+version: "3.2"
+services:
+  helper:
+    image: alpine
+    labels:
+      com.bloomberg.docket: run go tests
+      com.bloomberg.docket.expect-exit: "yes"
+`,
+		"docket.full.yaml.orig": "",
+		"docket.broken.yaml":    "services: [",
+	})
+
+	problems, err := Lint("docket")
+	s.Require().NoError(err)
+
+	s.Equal([]LintProblem{
+		{
+			Severity: LintError, Modes: nil, File: "docket.broken.yaml", Service: "",
+			Message: "failed to parse docket.broken.yaml: " +
+				"yaml: line 1: did not find expected node content",
+		},
+		{
+			Severity: LintError, Modes: []string{"debug"}, File: "docket.debug.yaml",
+			Service: "helper",
+			Message: `unrecognized docket label: "com.bloomberg.docket" : "run go tests"`,
+		},
+		{
+			Severity: LintError, Modes: []string{"debug"}, File: "docket.debug.yaml",
+			Service: "helper",
+			Message: `unrecognized docket label: "com.bloomberg.docket.expect-exit" : "yes"`,
+		},
+		{
+			Severity: LintError, Modes: []string{"full"}, File: "docket.full.yaml",
+			Service: "other",
+			Message: "multiple test services found (other, tester)",
+		},
+		{
+			Severity: LintError, Modes: []string{"full"}, File: "docket.full.yaml",
+			Service: "redis", Message: "the service has no image or build",
+		},
+		{
+			Severity: LintError, Modes: []string{"full"}, File: "docket.full.yaml",
+			Service: "redis", Message: `network "backend" isn't defined`,
+		},
+		{
+			Severity: LintWarning, Modes: nil, File: "docket.full.yaml.orig", Service: "",
+			Message: "no mode uses this file " +
+				"(docket uses docket.yaml, docket.MODE.yaml, and docket.MODE.*.yaml)",
+		},
+		{
+			Severity: LintWarning, Modes: []string{"debug", "full"}, File: "docket.yaml",
+			Service: "tester",
+			Message: "the test service's command (true) exits right away, so docket can't " +
+				"run the tests in it (try `command: sleep infinity`)",
+		},
+		{
+			Severity: LintError, Modes: []string{"full"}, File: "docket.yaml",
+			Service: "tester",
+			Message: "multiple test services found (other, tester)",
+		},
+	}, problems)
+}
+
+func (s *LintSuite) Test_lintUnusedFiles_GeneratedFiles() {
+	var problems lintProblems
+	lintUnusedFiles(&problems, "docket-go", []string{"docket-go.1234.567.work"})
+
+	s.Empty(problems.sorted())
+}
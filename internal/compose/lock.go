@@ -0,0 +1,336 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bloomberg/docket/internal/logging"
+	"gopkg.in/yaml.v2"
+)
+
+// ImageLock pins images to digests. `dkt lock` writes it to the lock file, and docket uses it
+// to generate a file that overrides the images of the services.
+type ImageLock struct {
+	// Images maps each image, like redis:6, to the image pinned to a digest, like
+	// redis@sha256:....
+	Images map[string]string `yaml:"images"`
+}
+
+const lockFileHeader = "# Generated by `dkt lock`. Run it again to update the digests.\n\n"
+
+// LockFile returns the name of the lock file for prefix, like docket.lock.
+func LockFile(prefix string) string {
+	return prefix + ".lock"
+}
+
+// ReadImageLock reads a lock file. It returns nil if the file doesn't exist.
+func ReadImageLock(path string) (*ImageLock, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read lock file: %w", err)
+	}
+
+	var lock ImageLock
+	if err := yaml.UnmarshalStrict(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return &lock, nil
+}
+
+// WriteImageLock writes a lock file.
+func WriteImageLock(path string, lock ImageLock) error {
+	data, err := yaml.Marshal(lock)
+	if err != nil {
+		return fmt.Errorf("failed to encode yaml: %w", err)
+	}
+
+	data = append([]byte(lockFileHeader), data...)
+	if err := ioutil.WriteFile(path, data, 0644); err != nil { //nolint:gosec // checked in
+		return fmt.Errorf("failed to write lock file: %w", err)
+	}
+
+	return nil
+}
+
+// ModeImages runs `docker-compose config` for every mode with prefix in the current directory
+// and returns the images that its services pull (the services that don't build their own),
+// with the modes that use each image.
+func ModeImages(ctx context.Context, prefix string) (map[string][]string, error) {
+	modes, err := FindModes(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	images := map[string][]string{}
+
+	for _, mode := range modes {
+		cmp, err := newConfigCompose(mode)
+		if err != nil {
+			return nil, err
+		}
+
+		if cmp.cfg, err = cmp.getAndParseConfig(ctx); err != nil {
+			return nil, err
+		}
+
+		for _, si := range cmp.PulledImages() {
+			imageModes := images[si.Image]
+			if len(imageModes) == 0 || imageModes[len(imageModes)-1] != mode.Name {
+				images[si.Image] = append(imageModes, mode.Name)
+			}
+		}
+	}
+
+	return images, nil
+}
+
+// newConfigCompose makes a Compose that can only run docker-compose with a mode's files.
+func newConfigCompose(mode Mode) (Compose, error) {
+	projectDir, err := filepath.Abs(filepath.Dir(mode.Files[0]))
+	if err != nil {
+		return Compose{}, fmt.Errorf("failed filepath.Abs: %w", err)
+	}
+
+	return Compose{
+		baseArgs:       append([]string{"--project-directory", projectDir}, makeFileArgs(mode.Files)...),
+		cfg:            cmpConfig{Version: "", Services: nil, Networks: nil},
+		mode:           mode.Name,
+		projectDir:     projectDir,
+		files:          mode.Files,
+		generatedFiles: nil,
+		testSvc:        "",
+		testBinaryDir:  "",
+		timings:        &Timings{},
+	}, nil
+}
+
+// ResolveImageDigest runs `docker pull` for image and returns the image pinned to the digest it
+// pulled, like redis@sha256:.... Images that are already pinned are returned as is.
+func ResolveImageDigest(ctx context.Context, image string) (string, error) {
+	if strings.Contains(image, "@") {
+		return image, nil
+	}
+
+	pull := exec.CommandContext(ctx, "docker", "pull", "--quiet", image)
+	logging.Get().Log(logging.LevelInfo, "pull", logging.Command(pull.Args))
+
+	if out, err := pull.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed docker pull %s: %w: %s", image, err, out)
+	}
+
+	inspect := exec.CommandContext(ctx,
+		"docker", "image", "inspect", "--format", "{{json .RepoDigests}}", image)
+
+	var stderr bytes.Buffer
+	inspect.Stderr = &stderr
+
+	out, err := inspect.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed docker image inspect %s: %w: %s", image, err, stderr.Bytes())
+	}
+
+	var repoDigests []string
+	if err := json.Unmarshal(out, &repoDigests); err != nil {
+		return "", fmt.Errorf("failed to parse the digests of %s: %w", image, err)
+	}
+
+	digest, err := pickDigest(imageRepository(image), repoDigests)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", image, err)
+	}
+
+	return imageRepository(image) + "@" + digest, nil
+}
+
+var errNoDigest = fmt.Errorf("no digest")
+
+// pickDigest picks the digest for repository out of an image's RepoDigests, which look like
+// redis@sha256:.... An image pulled under several names has several, so it prefers the one
+// for repository.
+func pickDigest(repository string, repoDigests []string) (string, error) {
+	digest := ""
+
+	for _, rd := range repoDigests {
+		i := strings.LastIndex(rd, "@")
+		if i < 0 {
+			continue
+		}
+
+		if rd[:i] == repository {
+			return rd[i+1:], nil
+		}
+
+		if digest == "" {
+			digest = rd[i+1:]
+		}
+	}
+
+	if digest == "" {
+		return "", errNoDigest
+	}
+
+	return digest, nil
+}
+
+// imageRepository returns an image reference without its tag or digest. Registries can have
+// ports, so the tag is only what comes after a colon in the last part of the path.
+func imageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+
+	return image
+}
+
+// LockDrift is a difference between the lock file and the docket files.
+type LockDrift struct {
+	Image   string
+	Modes   []string // the modes that use the image, if any do
+	Message string
+}
+
+// CheckImageLock compares lock to the images that ModeImages found.
+//
+// It only compares which images are locked, not their digests: checking whether a tag has
+// moved would mean asking the registry, which `dkt lock` does when it updates the digests.
+func CheckImageLock(lock ImageLock, images map[string][]string) []LockDrift {
+	var drift []LockDrift
+
+	for image, modes := range images {
+		if _, ok := lock.Images[image]; !ok {
+			drift = append(drift, LockDrift{Image: image, Modes: modes, Message: "not in the lock file"})
+		}
+	}
+
+	for image := range lock.Images {
+		if _, ok := images[image]; !ok {
+			drift = append(drift, LockDrift{Image: image, Modes: nil, Message: "no mode uses it"})
+		}
+	}
+
+	sort.Slice(drift, func(i, j int) bool { return drift[i].Image < drift[j].Image })
+
+	return drift
+}
+
+// doImageLock generates a file that pins the images of cfg's services to the digests in the
+// lock file at lockPath, unless DOCKET_IGNORE_LOCK is set or there's no lock file. It also pins
+// the images in cfg, so that the pull phase sees the pinned images.
+//
+// Images that aren't in the lock file are left alone, with a warning.
+func doImageLock(cfg cmpConfig, lockPath string) (
+	files []string, cleanup func() error, err error,
+) {
+	noop := func() error { return nil }
+
+	if os.Getenv("DOCKET_IGNORE_LOCK") != "" {
+		return nil, noop, nil
+	}
+
+	lock, err := ReadImageLock(lockPath)
+	if err != nil || lock == nil {
+		return nil, noop, err
+	}
+
+	lockCfg := newLockCfg(cfg, *lock)
+	if lockCfg == nil {
+		return nil, noop, nil
+	}
+
+	lockFile, err := createGeneratedFile("docket-image-lock.", ".yaml")
+	if err != nil {
+		return nil, noop, fmt.Errorf("failed to create image lock yaml: %w", err)
+	}
+
+	cleanup = func() error {
+		return removeGeneratedFile(lockFile.Name())
+	}
+
+	err = yaml.NewEncoder(lockFile).Encode(lockCfg)
+	if closeErr := lockFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = cleanup()
+
+		return nil, noop, fmt.Errorf("failed to write image lock yaml: %w", err)
+	}
+
+	for name, svc := range lockCfg.Services {
+		pinned := cfg.Services[name]
+		pinned.Image = svc.Image
+		cfg.Services[name] = pinned
+	}
+
+	return []string{lockFile.Name()}, cleanup, nil
+}
+
+// newLockCfg makes a cmpConfig that overrides the images of cfg's services with the ones in
+// lock. It returns nil if there's nothing to override.
+func newLockCfg(cfg cmpConfig, lock ImageLock) *cmpConfig {
+	lockCfg := cmpConfig{
+		Version:  "3.2",
+		Services: map[string]cmpService{},
+		Networks: nil,
+	}
+
+	for name, svc := range cfg.Services {
+		if svc.Image == "" || svc.Build != nil {
+			continue
+		}
+
+		pinned, ok := lock.Images[svc.Image]
+		if !ok {
+			logging.Get().Log(logging.LevelWarn, "image isn't in the lock file",
+				logging.Service(name), logging.Image(svc.Image))
+
+			continue
+		}
+
+		lockCfg.Services[name] = cmpService{
+			Build:       nil,
+			Command:     nil,
+			Environment: nil,
+			Image:       pinned,
+			Labels:      nil,
+			Ports:       nil,
+			Volumes:     nil,
+			WorkingDir:  "",
+		}
+	}
+
+	if len(lockCfg.Services) == 0 {
+		return nil
+	}
+
+	return &lockCfg
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v2"
+)
+
+func Test_Lock(t *testing.T) {
+	suite.Run(t, new(LockSuite))
+}
+
+type LockSuite struct {
+	suite.Suite
+
+	dir string
+}
+
+func (s *LockSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "docket-lock-test.")
+	s.Require().NoError(err)
+	s.dir = dir
+}
+
+func (s *LockSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+func (s *LockSuite) Test_ReadAndWriteImageLock() {
+	path := filepath.Join(s.dir, LockFile("docket"))
+
+	lock, err := ReadImageLock(path)
+	s.NoError(err)
+	s.Nil(lock)
+
+	written := ImageLock{Images: map[string]string{"redis:6": "redis@sha256:abc"}}
+	s.Require().NoError(WriteImageLock(path, written))
+
+	lock, err = ReadImageLock(path)
+	s.Require().NoError(err)
+	s.Equal(&written, lock)
+
+	s.Require().NoError(ioutil.WriteFile(path, []byte("imagez: {}\n"), 0600))
+	_, err = ReadImageLock(path)
+	s.Error(err)
+}
+
+func (s *LockSuite) Test_pickDigest() {
+	digest, err := pickDigest("example.com/redis",
+		[]string{"redis@sha256:abc", "example.com/redis@sha256:def"})
+	s.NoError(err)
+	s.Equal("sha256:def", digest)
+
+	digest, err = pickDigest("docker.io/library/redis", []string{"redis@sha256:abc"})
+	s.NoError(err)
+	s.Equal("sha256:abc", digest)
+
+	_, err = pickDigest("redis", nil)
+	s.Error(err)
+}
+
+func (s *LockSuite) Test_imageRepository() {
+	s.Equal("redis", imageRepository("redis"))
+	s.Equal("redis", imageRepository("redis:6"))
+	s.Equal("redis", imageRepository("redis@sha256:abc"))
+	s.Equal("localhost:5000/redis", imageRepository("localhost:5000/redis"))
+	s.Equal("localhost:5000/redis", imageRepository("localhost:5000/redis:6"))
+}
+
+func (s *LockSuite) Test_CheckImageLock() {
+	lock := ImageLock{Images: map[string]string{
+		"golang:1.16": "golang@sha256:abc",
+		"redis:5":     "redis@sha256:def",
+	}}
+	images := map[string][]string{
+		"golang:1.16": {"debug", "full"},
+		"redis:6":     {"full"},
+	}
+
+	s.Equal([]LockDrift{
+		{Image: "redis:5", Modes: nil, Message: "no mode uses it"},
+		{Image: "redis:6", Modes: []string{"full"}, Message: "not in the lock file"},
+	}, CheckImageLock(lock, images))
+
+	s.Empty(CheckImageLock(ImageLock{Images: map[string]string{"redis:6": "redis@sha256:def"}},
+		map[string][]string{"redis:6": {"full"}}))
+}
+
+func (s *LockSuite) Test_doImageLock() {
+	s.Require().NoError(os.Setenv("DOCKET_STATE_DIR", s.dir))
+	defer os.Unsetenv("DOCKET_STATE_DIR")
+
+	lockPath := filepath.Join(s.dir, LockFile("docket"))
+	s.Require().NoError(WriteImageLock(lockPath, ImageLock{Images: map[string]string{
+		"redis:6":     "redis@sha256:abc",
+		"example/app": "example/app@sha256:def",
+	}}))
+
+	cfg := cmpConfig{Version: "3.2", Networks: nil, Services: map[string]cmpService{
+		"redis":    {Image: "redis:6"},
+		"app":      {Image: "example/app", Build: "."},
+		"unlocked": {Image: "golang:1.16"},
+	}}
+
+	files, cleanup, err := doImageLock(cfg, lockPath)
+	s.Require().NoError(err)
+	s.Require().Len(files, 1)
+
+	data, err := ioutil.ReadFile(files[0])
+	s.Require().NoError(err)
+
+	var lockCfg cmpConfig
+	s.Require().NoError(yaml.Unmarshal(data, &lockCfg))
+	s.Equal(map[string]cmpService{"redis": {Image: "redis@sha256:abc"}}, lockCfg.Services)
+
+	s.Equal("redis@sha256:abc", cfg.Services["redis"].Image)
+	s.Equal("example/app", cfg.Services["app"].Image)
+	s.Equal("golang:1.16", cfg.Services["unlocked"].Image)
+
+	s.NoError(cleanup())
+	s.NoFileExists(files[0])
+}
+
+func (s *LockSuite) Test_doImageLock_Ignored() {
+	s.Require().NoError(os.Setenv("DOCKET_IGNORE_LOCK", "1"))
+	defer os.Unsetenv("DOCKET_IGNORE_LOCK")
+
+	lockPath := filepath.Join(s.dir, LockFile("docket"))
+	s.Require().NoError(WriteImageLock(lockPath, ImageLock{Images: map[string]string{
+		"redis:6": "redis@sha256:abc",
+	}}))
+
+	cfg := cmpConfig{Version: "3.2", Networks: nil, Services: map[string]cmpService{
+		"redis": {Image: "redis:6"},
+	}}
+
+	files, _, err := doImageLock(cfg, lockPath)
+	s.NoError(err)
+	s.Empty(files)
+	s.Equal("redis:6", cfg.Services["redis"].Image)
+}
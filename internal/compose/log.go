@@ -0,0 +1,108 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/bloomberg/docket/internal/logging"
+)
+
+// log logs a message with fields for c's mode and project.
+func (c Compose) log(level logging.Level, msg string, fields ...logging.Field) {
+	all := make([]logging.Field, 0, len(fields)+2)
+	all = append(all, logging.Mode(c.mode), logging.Project(c.ProjectName()))
+	all = append(all, fields...)
+
+	logging.Get().Log(level, msg, all...)
+}
+
+// logStep logs that a step is starting and returns a function that logs that it finished and
+// how long it took. It also records the step as a phase in c's Timings.
+func (c Compose) logStep(msg string, fields ...logging.Field) func() {
+	c.log(logging.LevelInfo, msg, fields...)
+
+	start := time.Now()
+
+	return func() {
+		d := time.Since(start)
+		c.timings.Record(msg, d)
+		c.log(logging.LevelInfo, msg+" finished", logging.Duration(d))
+	}
+}
+
+// logWriter logs each line written to it as the "output" field of a message. It keeps the last
+// lines for error messages.
+type logWriter struct {
+	c     Compose
+	msg   string
+	mu    sync.Mutex
+	buf   []byte   // the current line, until it's complete
+	tail  []string // the last nTail lines
+	nTail int
+}
+
+func (c Compose) newLogWriter(msg string, tailLines int) *logWriter {
+	return &logWriter{c: c, msg: msg, mu: sync.Mutex{}, buf: nil, tail: nil, nTail: tailLines}
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		w.logLine(string(bytes.TrimRight(w.buf[:i], "\r")))
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush logs the last line if it doesn't end with a newline.
+func (w *logWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		w.logLine(string(w.buf))
+		w.buf = nil
+	}
+}
+
+func (w *logWriter) logLine(line string) {
+	w.c.log(logging.LevelInfo, w.msg, logging.Output(line))
+
+	w.tail = append(w.tail, line)
+	if len(w.tail) > w.nTail {
+		w.tail = w.tail[len(w.tail)-w.nTail:]
+	}
+}
+
+// Tail returns the last lines that were written.
+func (w *logWriter) Tail() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]string(nil), w.tail...)
+}
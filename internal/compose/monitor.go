@@ -0,0 +1,181 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bloomberg/docket/internal/logging"
+)
+
+// monitorLogLines is how many lines of a service's logs a ServiceExitedError includes.
+const monitorLogLines = 20
+
+// ServiceExitedError reports a service that exited or restarted while the tests ran.
+type ServiceExitedError struct {
+	Service   string
+	ExitCode  int
+	Restarted bool
+	Logs      string // the last lines of the service's logs
+}
+
+func (e *ServiceExitedError) Error() string {
+	var b strings.Builder
+
+	if e.Restarted {
+		fmt.Fprintf(&b, "service %q restarted while the tests ran", e.Service)
+	} else {
+		fmt.Fprintf(&b, "service %q exited with code %d while the tests ran", e.Service, e.ExitCode)
+	}
+
+	if logs := strings.TrimRight(e.Logs, "\n"); logs != "" {
+		fmt.Fprintf(&b, "; its last log lines:\n%s", logs)
+	}
+
+	return b.String()
+}
+
+// Monitor watches the app's containers while the tests run. When a service that isn't labeled
+// com.bloomberg.docket.expect-exit exits or restarts, it calls onExit and cancels its Context.
+type Monitor struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	stopOnce sync.Once
+	err      *ServiceExitedError // only read after done is closed
+}
+
+// StartMonitor starts a Monitor that checks the containers every interval. The containers'
+// current states are the baseline for noticing restarts.
+func (c Compose) StartMonitor(
+	ctx context.Context, interval time.Duration, onExit func(*ServiceExitedError),
+) (*Monitor, error) {
+	baseline, err := c.ContainerStates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	monitorCtx, cancel := context.WithCancel(ctx)
+	m := &Monitor{
+		ctx:      monitorCtx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		stopOnce: sync.Once{},
+		err:      nil,
+	}
+
+	c.log(logging.LevelDebug, "monitoring containers", logging.Duration(interval))
+
+	go c.monitor(m, baseline, interval, onExit)
+
+	return m, nil
+}
+
+func (c Compose) monitor(
+	m *Monitor, baseline []ContainerState, interval time.Duration,
+	onExit func(*ServiceExitedError),
+) {
+	defer close(m.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		states, err := c.ContainerStates(m.ctx)
+		if err != nil {
+			if m.ctx.Err() == nil {
+				c.log(logging.LevelWarn, "failed to check containers", logging.Error(err))
+			}
+
+			continue
+		}
+
+		exited := findUnexpectedExit(baseline, states)
+		if exited == nil {
+			continue
+		}
+
+		if logs, err := c.Logs(m.ctx, monitorLogLines, exited.Service); err == nil {
+			exited.Logs = string(logs)
+		}
+
+		c.log(logging.LevelError, "service exited", logging.Service(exited.Service),
+			logging.Field{Key: "exit_code", Value: exited.ExitCode},
+			logging.Field{Key: "restarted", Value: exited.Restarted})
+
+		m.err = exited
+		onExit(exited)
+		m.cancel()
+
+		return
+	}
+}
+
+// findUnexpectedExit returns the first container in states that stopped or restarted since
+// baseline, unless its service expects to exit. Containers that had already stopped in
+// baseline are ignored.
+func findUnexpectedExit(baseline, states []ContainerState) *ServiceExitedError {
+	before := make(map[string]ContainerState, len(baseline))
+	for _, s := range baseline {
+		before[s.Container] = s
+	}
+
+	for _, s := range states {
+		prev, seen := before[s.Container]
+		if s.ExpectExit || (seen && prev.Stopped()) {
+			continue
+		}
+
+		switch {
+		case s.Stopped():
+			return &ServiceExitedError{
+				Service: s.Service, ExitCode: s.ExitCode, Restarted: false, Logs: "",
+			}
+		case s.RestartCount > prev.RestartCount:
+			return &ServiceExitedError{
+				Service: s.Service, ExitCode: s.ExitCode, Restarted: true, Logs: "",
+			}
+		}
+	}
+
+	return nil
+}
+
+// Context returns a context that's canceled when a service exits unexpectedly.
+func (m *Monitor) Context() context.Context {
+	return m.ctx
+}
+
+// Stop stops the Monitor and returns the service that exited, if any. It's safe to call more
+// than once.
+func (m *Monitor) Stop() *ServiceExitedError {
+	m.stopOnce.Do(func() {
+		m.cancel()
+		<-m.done
+	})
+
+	return m.err
+}
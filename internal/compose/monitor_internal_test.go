@@ -0,0 +1,113 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func Test_Monitor(t *testing.T) {
+	suite.Run(t, new(MonitorSuite))
+}
+
+type MonitorSuite struct {
+	suite.Suite
+}
+
+func (s *MonitorSuite) Test_parseContainerStates() {
+	c := Compose{cfg: cmpConfig{Services: map[string]cmpService{
+		"migrate": {Labels: map[string]string{expectExitLabelKey: "true"}},
+		"redis":   {},
+	}}}
+
+	states, err := c.parseContainerStates([]byte(`[
+		{
+			"Name": "/app_redis_1",
+			"State": {"Status": "running", "ExitCode": 0, "Health": {"Status": "healthy"}},
+			"RestartCount": 2,
+			"Config": {"Labels": {"com.docker.compose.service": "redis"}}
+		},
+		{
+			"Name": "/app_migrate_1",
+			"State": {"Status": "exited", "ExitCode": 0},
+			"RestartCount": 0,
+			"Config": {"Labels": {"com.docker.compose.service": "migrate"}}
+		}
+	]`))
+	s.Require().NoError(err)
+
+	s.Equal([]ContainerState{
+		{
+			Service: "migrate", Container: "app_migrate_1", Status: "exited", Health: "",
+			ExitCode: 0, RestartCount: 0, ExpectExit: true,
+		},
+		{
+			Service: "redis", Container: "app_redis_1", Status: "running", Health: "healthy",
+			ExitCode: 0, RestartCount: 2, ExpectExit: false,
+		},
+	}, states)
+
+	_, err = c.parseContainerStates([]byte(`not json`))
+	s.Error(err)
+}
+
+func state(service, status string, exitCode, restarts int, expectExit bool) ContainerState {
+	return ContainerState{
+		Service:      service,
+		Container:    "app_" + service + "_1",
+		Status:       status,
+		Health:       "",
+		ExitCode:     exitCode,
+		RestartCount: restarts,
+		ExpectExit:   expectExit,
+	}
+}
+
+func (s *MonitorSuite) Test_findUnexpectedExit() {
+	baseline := []ContainerState{
+		state("migrate", "running", 0, 0, true),
+		state("old", "exited", 1, 0, false),
+		state("redis", "running", 0, 0, false),
+	}
+
+	s.Nil(findUnexpectedExit(baseline, baseline))
+
+	// Expected exits and containers that had already stopped are fine.
+	s.Nil(findUnexpectedExit(baseline, []ContainerState{
+		state("migrate", "exited", 0, 0, true),
+		state("old", "exited", 1, 0, false),
+		state("redis", "running", 0, 0, false),
+	}))
+
+	s.Equal(&ServiceExitedError{Service: "redis", ExitCode: 137, Restarted: false, Logs: ""},
+		findUnexpectedExit(baseline, []ContainerState{state("redis", "exited", 137, 0, false)}))
+
+	s.Equal(&ServiceExitedError{Service: "redis", ExitCode: 1, Restarted: true, Logs: ""},
+		findUnexpectedExit(baseline, []ContainerState{state("redis", "running", 1, 1, false)}))
+}
+
+func (s *MonitorSuite) Test_ServiceExitedError() {
+	s.Equal(`service "redis" exited with code 1 while the tests ran; its last log lines:
+redis_1  | bad config
+redis_1  | exiting`, (&ServiceExitedError{
+		Service: "redis", ExitCode: 1, Restarted: false,
+		Logs: "redis_1  | bad config\nredis_1  | exiting\n",
+	}).Error())
+
+	s.Equal(`service "redis" restarted while the tests ran`,
+		(&ServiceExitedError{Service: "redis", ExitCode: 1, Restarted: true, Logs: ""}).Error())
+}
@@ -15,20 +15,31 @@
 package compose
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/bloomberg/docket/internal/statedir"
 	"gopkg.in/yaml.v2"
 )
 
-func doSourceMounts(cfg cmpConfig, goList goList, goPath []string) (
-	args []string, cleanup func() error, err error,
+func doSourceMounts(
+	cfg cmpConfig, project string, goList goList, goPath []string, workspace *goWorkspace,
+	testBinaryDir string,
+) (
+	files []string, cleanup func() error, err error,
 ) {
 	noop := func() error { return nil }
 
-	mountsCfg, err := newMountsCfg(cfg, goList, goPath)
+	mounts, err := makeSourceMounts(goList, goPath, workspace)
+	if err != nil {
+		return nil, noop, err
+	}
+
+	mountsCfg, err := newMountsCfg(cfg, mounts, testBinaryDir)
 	if err != nil {
 		return nil, noop, err
 	}
@@ -36,24 +47,31 @@ func doSourceMounts(cfg cmpConfig, goList goList, goPath []string) (
 		return nil, noop, nil
 	}
 
-	mountsFile, err := ioutil.TempFile(".", "docket-source-mounts.*.yaml")
+	if workspace != nil && workspace.goWork != "" {
+		goWorkPath, err := writeGoWorkFile(project, *workspace)
+		if err != nil {
+			return nil, noop, err
+		}
+
+		addGoWorkFile(mountsCfg, goWorkPath)
+	}
+
+	if err := addForwardedEnv(mountsCfg, os.Getenv("DOCKET_FORWARD_ENV"), os.LookupEnv); err != nil {
+		return nil, noop, err
+	}
+
+	mountsFile, err := createGeneratedFile("docket-source-mounts.", ".yaml")
 	if err != nil {
 		return nil, noop, fmt.Errorf("failed to create source mounts yaml: %w", err)
 	}
 
 	cleanup = func() error {
-		if os.Getenv("DOCKET_KEEP_MOUNTS_FILE") != "" {
-			tracef("Leaving %s alone\n", mountsFile.Name())
-
-			return nil
-		}
-
-		return os.Remove(mountsFile.Name())
+		return removeGeneratedFile(mountsFile.Name())
 	}
 
 	defer func() {
 		if closeErr := mountsFile.Close(); closeErr != nil {
-			args = nil
+			files = nil
 			err = closeErr
 		}
 	}()
@@ -62,57 +80,133 @@ func doSourceMounts(cfg cmpConfig, goList goList, goPath []string) (
 
 	defer func() {
 		if closeErr := enc.Close(); closeErr != nil {
-			args = nil
+			files = nil
 			err = closeErr
 		}
 	}()
 
 	if err := enc.Encode(mountsCfg); err != nil {
-		return nil, noop, fmt.Errorf("failed to encode yaml: %w", err)
+		return nil, cleanup, fmt.Errorf("failed to encode yaml: %w", err)
 	}
 
-	return []string{"--file", mountsFile.Name()}, cleanup, nil
+	return []string{mountsFile.Name()}, cleanup, nil
 }
 
-var errMultipleGOPATHs = fmt.Errorf("docket doesn't support multipart GOPATHs")
+// writeGoWorkFile writes the container's go.work file and returns its path.
+//
+// The path is the same for every run with the same project and host go.work file, so that
+// the services' bind mounts (and so their configs) don't change from run to run, which would
+// make `docker-compose up` recreate containers while other processes are using them. The file
+// is only rewritten when its contents change.
+func writeGoWorkFile(project string, workspace goWorkspace) (string, error) {
+	h := sha256.Sum256([]byte(project + "\x00" + workspace.goWork))
+
+	dir, err := statedir.Dir("go-work", hex.EncodeToString(h[:])[:16])
+	if err != nil {
+		return "", err
+	}
 
-type mountsFunc func(goList, []string) ([]cmpVolume, string, error)
+	goWorkPath := filepath.Join(dir, "go.work")
+	if err := writeFileIfChanged(goWorkPath, makeGoWorkFile(workspace)); err != nil {
+		return "", fmt.Errorf("failed to write go.work file: %w", err)
+	}
 
-// newMountsCfg makes a cmpConfig to bind mount Go sources for the services that need them.
-func newMountsCfg(originalCfg cmpConfig, goList goList, goPath []string) (*cmpConfig, error) {
-	mountsCfg := cmpConfig{
-		Version:  "3.2",
-		Services: map[string]cmpService{},
-		Networks: nil,
+	return goWorkPath, nil
+}
+
+// addGoWorkFile mounts the generated go.work file into every service in mountsCfg and tells the
+// go command to use it.
+func addGoWorkFile(mountsCfg *cmpConfig, goWorkPath string) {
+	for name, svc := range mountsCfg.Services {
+		volumes := make([]cmpVolume, 0, len(svc.Volumes)+1)
+		volumes = append(volumes, svc.Volumes...)
+		svc.Volumes = append(volumes, cmpVolume{
+			Type:   "bind",
+			Source: goWorkPath,
+			Target: goWorkTarget,
+		})
+
+		svc.Environment = mergeEnvironment(svc.Environment, map[string]string{"GOWORK": goWorkTarget})
+
+		mountsCfg.Services[name] = svc
 	}
+}
 
-	if len(goPath) != 1 {
-		return nil, errMultipleGOPATHs
+// mergeEnvironment returns a copy of a service's environment with values added to it.
+func mergeEnvironment(environment map[string]*string, values map[string]string) map[string]*string {
+	if len(environment) == 0 && len(values) == 0 {
+		return nil
 	}
 
-	var mountsFunc mountsFunc
-	if goList.Module == nil {
-		mountsFunc = mountsForModuleMode
-	} else {
-		mountsFunc = mountsForGOPATHMode
+	merged := make(map[string]*string, len(environment)+len(values))
+	for k, v := range environment {
+		merged[k] = v
 	}
-	volumes, workingDir, err := mountsFunc(goList, goPath)
-	if err != nil {
-		return nil, err
+	for k, v := range values {
+		v := v
+		merged[k] = &v
+	}
+
+	return merged
+}
+
+// sourceMounts describes how to make Go sources available inside a service.
+type sourceMounts struct {
+	volumes     []cmpVolume
+	workingDir  string
+	environment map[string]string
+}
+
+func makeSourceMounts(goList goList, goPath []string, workspace *goWorkspace) (
+	sourceMounts, error,
+) {
+	switch {
+	case goList.Module == nil:
+		return mountsForModuleMode(goList, goPath)
+	case workspace != nil:
+		return mountsForWorkspace(goList, goPath, *workspace)
+	default:
+		return mountsForGOPATHMode(goList, goPath)
+	}
+}
+
+// newMountsCfg makes a cmpConfig to bind mount Go sources for the services that need them.
+//
+// If testBinaryDir is non-empty, it is also mounted into the service that runs the test binary.
+func newMountsCfg(
+	originalCfg cmpConfig, mounts sourceMounts, testBinaryDir string,
+) (*cmpConfig, error) {
+	mountsCfg := cmpConfig{
+		Version:  "3.2",
+		Services: map[string]cmpService{},
+		Networks: nil,
 	}
 
 	for name, svc := range originalCfg.Services {
-		if _, mountGoSources, err := parseDocketLabel(svc); err != nil {
+		label, err := parseDocketLabel(svc)
+		if err != nil {
 			return nil, err
-		} else if mountGoSources {
-			mountsCfg.Services[name] = cmpService{
-				Command:     nil,
-				Environment: nil,
-				Image:       "",
-				Labels:      nil,
-				Volumes:     volumes,
-				WorkingDir:  workingDir,
-			}
+		}
+		if !label.mountGoSources {
+			continue
+		}
+
+		volumes := mounts.volumes
+		if label.testBinary && testBinaryDir != "" {
+			volumes = make([]cmpVolume, 0, len(mounts.volumes)+1)
+			volumes = append(volumes, mounts.volumes...)
+			volumes = append(volumes, testBinaryVolume(testBinaryDir))
+		}
+
+		mountsCfg.Services[name] = cmpService{
+			Build:       nil,
+			Command:     nil,
+			Environment: mergeEnvironment(nil, mounts.environment),
+			Image:       "",
+			Labels:      nil,
+			Ports:       nil,
+			Volumes:     volumes,
+			WorkingDir:  mounts.workingDir,
 		}
 	}
 
@@ -123,34 +217,85 @@ func newMountsCfg(originalCfg cmpConfig, goList goList, goPath []string) (*cmpCo
 	return &mountsCfg, nil
 }
 
-func mountsForModuleMode(goList goList, goPath []string) ([]cmpVolume, string, error) {
+var (
+	errEmptyGOPATH     = fmt.Errorf("GOPATH is empty")
+	errAmbiguousGOPATH = fmt.Errorf("package is inside more than one GOPATH entry")
+)
+
+// mountsForModuleMode mounts every GOPATH entry. The entry containing the package is mounted at
+// /go, and any other entries are mounted at /go-gopath-N (where N is the entry's index), with
+// GOPATH set to match so that imports resolve the same way they do on the host.
+func mountsForModuleMode(goList goList, goPath []string) (sourceMounts, error) {
 	const goPathTarget = "/go"
 
-	pkgName, err := findPackageNameFromDirAndGOPATH(goList.Dir, goPath)
-	if err != nil {
-		return nil, "", err
+	goPath = uniqueGOPATHEntries(goPath)
+
+	var pkgEntry int
+	var pkgName string
+	var containingEntries []string
+	for i, gp := range goPath {
+		name, err := findPackageNameFromDirAndGOPATH(goList.Dir, []string{gp})
+		if err != nil {
+			continue
+		}
+		pkgEntry, pkgName = i, name
+		containingEntries = append(containingEntries, gp)
 	}
 
-	volumes := []cmpVolume{
-		{
+	if len(containingEntries) == 0 {
+		// Use the original error, which explains which entries we looked at.
+		_, err := findPackageNameFromDirAndGOPATH(goList.Dir, goPath)
+
+		return sourceMounts{}, err
+	}
+
+	if len(containingEntries) > 1 {
+		return sourceMounts{}, fmt.Errorf(
+			"%w: package dir %q is under the src dirs of GOPATH entries %q, so docket cannot tell "+
+				"which entry to mount at %s", errAmbiguousGOPATH, goList.Dir, containingEntries, goPathTarget)
+	}
+
+	mounts := sourceMounts{
+		volumes:     make([]cmpVolume, 0, len(goPath)),
+		workingDir:  fmt.Sprintf("%s/src/%s", goPathTarget, pkgName),
+		environment: nil,
+	}
+
+	targets := make([]string, len(goPath))
+	for i, gp := range goPath {
+		targets[i] = goPathTarget
+		if i != pkgEntry {
+			targets[i] = fmt.Sprintf("%s-gopath-%d", goPathTarget, i)
+		}
+
+		mounts.volumes = append(mounts.volumes, cmpVolume{
 			Type:   "bind",
-			Source: goPath[0],
-			Target: goPathTarget,
-		},
+			Source: gp,
+			Target: targets[i],
+		})
 	}
 
-	workingDir := fmt.Sprintf("%s/src/%s", goPathTarget, pkgName)
+	if len(goPath) > 1 {
+		mounts.environment = map[string]string{"GOPATH": strings.Join(targets, ":")}
+	}
 
-	return volumes, workingDir, nil
+	return mounts, nil
 }
 
-func mountsForGOPATHMode(goList goList, goPath []string) ([]cmpVolume, string, error) {
+// mountsForGOPATHMode mounts the module cache from the first GOPATH entry (which is where the go
+// command keeps it) and the module's directory.
+func mountsForGOPATHMode(goList goList, goPath []string) (sourceMounts, error) {
 	const goPathTarget = "/go"
 	const goModuleDirTarget = "/go-module-dir"
 
+	goPath = uniqueGOPATHEntries(goPath)
+	if len(goPath) == 0 {
+		return sourceMounts{}, errEmptyGOPATH
+	}
+
 	pathInsideModule, err := filepath.Rel(goList.Module.Dir, goList.Dir)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed filepath.Rel: %w", err)
+		return sourceMounts{}, fmt.Errorf("failed filepath.Rel: %w", err)
 	}
 
 	volumes := []cmpVolume{
@@ -166,7 +311,31 @@ func mountsForGOPATHMode(goList goList, goPath []string) ([]cmpVolume, string, e
 		},
 	}
 
-	workingDir := fmt.Sprintf("%s/%s", goModuleDirTarget, filepath.ToSlash(pathInsideModule))
+	return sourceMounts{
+		volumes:     volumes,
+		workingDir:  fmt.Sprintf("%s/%s", goModuleDirTarget, filepath.ToSlash(pathInsideModule)),
+		environment: nil,
+	}, nil
+}
+
+// uniqueGOPATHEntries drops empty and repeated GOPATH entries, keeping the first occurrence.
+func uniqueGOPATHEntries(goPath []string) []string {
+	seen := make(map[string]bool, len(goPath))
+	unique := make([]string, 0, len(goPath))
+
+	for _, gp := range goPath {
+		if gp == "" {
+			continue
+		}
+
+		gp = filepath.Clean(gp)
+		if seen[gp] {
+			continue
+		}
+		seen[gp] = true
+
+		unique = append(unique, gp)
+	}
 
-	return volumes, workingDir, nil
+	return unique
 }
@@ -0,0 +1,97 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func Test_Mounts(t *testing.T) {
+	suite.Run(t, new(MountsSuite))
+}
+
+type MountsSuite struct {
+	suite.Suite
+}
+
+func (s *MountsSuite) Test_mountsForModuleMode_SingleGOPATH() {
+	gl := goList{Dir: filepath.FromSlash("/go/src/example.com/pkg"), ImportPath: "", Module: nil}
+
+	mounts, err := mountsForModuleMode(gl, []string{filepath.FromSlash("/go")})
+	s.Require().NoError(err)
+
+	s.Equal([]cmpVolume{{Type: "bind", Source: filepath.FromSlash("/go"), Target: "/go"}},
+		mounts.volumes)
+	s.Equal("/go/src/example.com/pkg", mounts.workingDir)
+	s.Nil(mounts.environment)
+}
+
+func (s *MountsSuite) Test_mountsForModuleMode_MultipleGOPATHs() {
+	gl := goList{Dir: filepath.FromSlash("/b/src/example.com/pkg"), ImportPath: "", Module: nil}
+	goPath := []string{
+		filepath.FromSlash("/a"),
+		filepath.FromSlash("/b"),
+		filepath.FromSlash("/a/"), // duplicate
+		filepath.FromSlash("/c"),
+	}
+
+	mounts, err := mountsForModuleMode(gl, goPath)
+	s.Require().NoError(err)
+
+	s.Equal([]cmpVolume{
+		{Type: "bind", Source: filepath.FromSlash("/a"), Target: "/go-gopath-0"},
+		{Type: "bind", Source: filepath.FromSlash("/b"), Target: "/go"},
+		{Type: "bind", Source: filepath.FromSlash("/c"), Target: "/go-gopath-2"},
+	}, mounts.volumes)
+	s.Equal("/go/src/example.com/pkg", mounts.workingDir)
+	s.Equal(map[string]string{"GOPATH": "/go-gopath-0:/go:/go-gopath-2"}, mounts.environment)
+}
+
+func (s *MountsSuite) Test_mountsForModuleMode_Errors() {
+	gl := goList{Dir: filepath.FromSlash("/a/src/b/src/pkg"), ImportPath: "", Module: nil}
+
+	_, err := mountsForModuleMode(gl, []string{filepath.FromSlash("/a"), filepath.FromSlash("/a/src/b")})
+	s.True(errors.Is(err, errAmbiguousGOPATH), err)
+	s.Contains(err.Error(), filepath.FromSlash("/a/src/b"))
+
+	_, err = mountsForModuleMode(gl, []string{filepath.FromSlash("/elsewhere")})
+	s.True(errors.Is(err, errPackageNameNotFound), err)
+}
+
+func (s *MountsSuite) Test_mountsForGOPATHMode() {
+	gl := goList{Dir: filepath.FromSlash("/src/mod/pkg"), ImportPath: "", Module: nil}
+	gl.Module = &struct {
+		Path string
+		Dir  string
+	}{Path: "example.com/mod", Dir: filepath.FromSlash("/src/mod")}
+
+	goPath := []string{filepath.FromSlash("/first"), filepath.FromSlash("/second")}
+
+	mounts, err := mountsForGOPATHMode(gl, goPath)
+	s.Require().NoError(err)
+
+	s.Equal([]cmpVolume{
+		{Type: "bind", Source: filepath.FromSlash("/first/pkg/mod"), Target: "/go/pkg/mod"},
+		{Type: "bind", Source: filepath.FromSlash("/src/mod"), Target: "/go-module-dir"},
+	}, mounts.volumes)
+	s.Equal("/go-module-dir/pkg", mounts.workingDir)
+
+	_, err = mountsForGOPATHMode(gl, nil)
+	s.True(errors.Is(err, errEmptyGOPATH), err)
+}
@@ -0,0 +1,212 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bloomberg/docket/internal/logging"
+)
+
+// PublishedPort is a service's private port that is published on the host.
+type PublishedPort struct {
+	Service  string
+	Port     int    // the private port inside the service's container
+	Protocol string // "tcp" or "udp"
+	HostAddr string // host:port address to use from the host
+}
+
+// PublishedPorts runs `docker-compose port` for every port the running services publish and
+// returns the host addresses to use to reach them. It skips services that aren't running (like
+// one-shot jobs that have finished) and ports that don't have a host address.
+func (c Compose) PublishedPorts(ctx context.Context) ([]PublishedPort, error) {
+	states, err := c.ContainerStates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	running := runningServices(states)
+
+	var ports []PublishedPort
+
+	for name, svc := range c.cfg.Services {
+		svcPorts, err := parsePorts(svc.Ports)
+		if err != nil {
+			return nil, fmt.Errorf("service %q: %w", name, err)
+		}
+
+		if len(svcPorts) > 0 && !running[name] {
+			c.log(logging.LevelDebug, "skipping the ports of a service that isn't running",
+				logging.Service(name))
+
+			continue
+		}
+
+		for _, p := range svcPorts {
+			addr, err := c.publishedAddr(ctx, name, p.port, p.protocol)
+			if errors.Is(err, errPortNotFound) {
+				c.log(logging.LevelDebug, "skipping a port that isn't published",
+					logging.Service(name))
+
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+
+			ports = append(ports, PublishedPort{
+				Service:  name,
+				Port:     p.port,
+				Protocol: p.protocol,
+				HostAddr: addr,
+			})
+		}
+	}
+
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Service != ports[j].Service {
+			return ports[i].Service < ports[j].Service
+		}
+		if ports[i].Port != ports[j].Port {
+			return ports[i].Port < ports[j].Port
+		}
+
+		return ports[i].Protocol < ports[j].Protocol
+	})
+
+	return ports, nil
+}
+
+// runningServices returns the services with a running container.
+func runningServices(states []ContainerState) map[string]bool {
+	running := map[string]bool{}
+	for _, s := range states {
+		if s.Status == "running" {
+			running[s.Service] = true
+		}
+	}
+
+	return running
+}
+
+func (c Compose) publishedAddr(ctx context.Context, service string, port int, protocol string) (
+	string, error,
+) {
+	cmd := c.Command(ctx, "port", "--protocol="+protocol, service, strconv.Itoa(port))
+
+	c.log(logging.LevelInfo, "port", logging.Service(service), logging.Command(cmd.Args))
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("port error: err=%w out=%q", err, out)
+	}
+
+	host, hostPort, err := net.SplitHostPort(string(bytes.TrimSpace(out)))
+	if err != nil || hostPort == "" || hostPort == "0" {
+		return "", fmt.Errorf("%w: %q", errPortNotFound, out)
+	}
+
+	switch host {
+	case "", "0.0.0.0", "::":
+		host = "127.0.0.1"
+	}
+
+	return net.JoinHostPort(host, hostPort), nil
+}
+
+type servicePort struct {
+	port     int
+	protocol string
+}
+
+var errBadPortSpec = fmt.Errorf("bad port specification")
+
+// parsePorts finds the private ports in a service's ports section. Each entry can use the short
+// syntax ("6379", "8080:80/udp", "127.0.0.1:3000-3001:3000-3001") or the long syntax (a map
+// with target and protocol).
+func parsePorts(specs []interface{}) ([]servicePort, error) {
+	var ports []servicePort
+
+	for _, spec := range specs {
+		switch spec := spec.(type) {
+		case string:
+			parsed, err := parseShortPortSpec(spec)
+			if err != nil {
+				return nil, err
+			}
+			ports = append(ports, parsed...)
+
+		case int:
+			ports = append(ports, servicePort{port: spec, protocol: "tcp"})
+
+		case map[interface{}]interface{}:
+			target, ok := spec["target"].(int)
+			if !ok {
+				return nil, fmt.Errorf("%w: missing target: %v", errBadPortSpec, spec)
+			}
+
+			protocol, _ := spec["protocol"].(string)
+			if protocol == "" {
+				protocol = "tcp"
+			}
+
+			ports = append(ports, servicePort{port: target, protocol: protocol})
+
+		default:
+			return nil, fmt.Errorf("%w: %v", errBadPortSpec, spec)
+		}
+	}
+
+	return ports, nil
+}
+
+func parseShortPortSpec(spec string) ([]servicePort, error) {
+	protocol := "tcp"
+	portsPart := spec
+	if i := strings.LastIndex(spec, "/"); i >= 0 {
+		portsPart, protocol = spec[:i], spec[i+1:]
+	}
+
+	// The private port (or range) is always last.
+	if i := strings.LastIndex(portsPart, ":"); i >= 0 {
+		portsPart = portsPart[i+1:]
+	}
+
+	first, last := portsPart, portsPart
+	if i := strings.Index(portsPart, "-"); i >= 0 {
+		first, last = portsPart[:i], portsPart[i+1:]
+	}
+
+	firstPort, err := strconv.Atoi(first)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", errBadPortSpec, spec)
+	}
+	lastPort, err := strconv.Atoi(last)
+	if err != nil || lastPort < firstPort {
+		return nil, fmt.Errorf("%w: %q", errBadPortSpec, spec)
+	}
+
+	ports := make([]servicePort, 0, lastPort-firstPort+1)
+	for p := firstPort; p <= lastPort; p++ {
+		ports = append(ports, servicePort{port: p, protocol: protocol})
+	}
+
+	return ports, nil
+}
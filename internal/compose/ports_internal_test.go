@@ -0,0 +1,80 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func Test_Ports(t *testing.T) {
+	suite.Run(t, new(PortsSuite))
+}
+
+type PortsSuite struct {
+	suite.Suite
+}
+
+func (s *PortsSuite) Test_parsePorts() {
+	specs := []interface{}{
+		"6379",
+		"8080:80",
+		"127.0.0.1:5353:53/udp",
+		"[::1]:9000:9000",
+		"3000-3002",
+		443,
+		map[interface{}]interface{}{"target": 5432, "published": 15432},
+		map[interface{}]interface{}{"target": 514, "protocol": "udp"},
+	}
+
+	ports, err := parsePorts(specs)
+	s.Require().NoError(err)
+
+	s.Equal([]servicePort{
+		{port: 6379, protocol: "tcp"},
+		{port: 80, protocol: "tcp"},
+		{port: 53, protocol: "udp"},
+		{port: 9000, protocol: "tcp"},
+		{port: 3000, protocol: "tcp"},
+		{port: 3001, protocol: "tcp"},
+		{port: 3002, protocol: "tcp"},
+		{port: 443, protocol: "tcp"},
+		{port: 5432, protocol: "tcp"},
+		{port: 514, protocol: "udp"},
+	}, ports)
+}
+
+func (s *PortsSuite) Test_parsePorts_Bad() {
+	badSpecs := []interface{}{
+		"http",
+		"3002-3000",
+		map[interface{}]interface{}{"published": 80},
+		1.5,
+	}
+
+	for _, spec := range badSpecs {
+		_, err := parsePorts([]interface{}{spec})
+		s.Error(err, "spec: %v", spec)
+	}
+}
+
+func (s *PortsSuite) Test_runningServices() {
+	s.Equal(map[string]bool{"redis": true}, runningServices([]ContainerState{
+		state("migrate", "exited", 0, 0, true),
+		state("redis", "running", 0, 0, false),
+		state("web", "restarting", 1, 2, false),
+	}))
+}
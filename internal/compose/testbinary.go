@@ -0,0 +1,182 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/bloomberg/docket/internal/logging"
+	"github.com/bloomberg/docket/internal/statedir"
+	"github.com/bloomberg/docket/internal/tempbuild"
+)
+
+// testBinaryTarget is where the host's test binary directory is mounted inside the test service.
+const testBinaryTarget = "/docket-test-binary"
+
+// testBinaryName is the name of the test binary inside the test binary directory.
+const testBinaryName = "docket.test"
+
+// makeTestBinaryDir makes a host directory to hold the test binary built for the test service.
+//
+// The directory is the same for every run of a package with the same project, so that the test
+// service's bind mount (and so its config) doesn't change from run to run, which would make
+// `docker-compose up` recreate the container while other processes are using it.
+func makeTestBinaryDir(project, pkgDir string) (string, error) {
+	h := sha256.Sum256([]byte(project + "\x00" + pkgDir))
+
+	dir, err := statedir.Dir("test-binaries", hex.EncodeToString(h[:])[:16])
+	if err != nil {
+		return "", err
+	}
+
+	// The container might not run as the same user as we do.
+	if err := os.Chmod(dir, 0755); err != nil { //nolint:gosec // not secret
+		return "", fmt.Errorf("failed to chmod test binary dir: %w", err)
+	}
+
+	return dir, nil
+}
+
+func testBinaryVolume(testBinaryDir string) cmpVolume {
+	return cmpVolume{
+		Type:   "bind",
+		Source: testBinaryDir,
+		Target: testBinaryTarget,
+	}
+}
+
+// UsesTestBinary reports whether the test service is labeled "run test binary".
+func (c Compose) UsesTestBinary() bool {
+	return c.testBinaryDir != ""
+}
+
+// testBinaryEnv returns the variables to add to the environment when building test binaries.
+// GOARCH is the architecture of the Docker daemon, which might not be the host's.
+func testBinaryEnv(ctx context.Context) ([]string, error) {
+	arch, err := dockerServerArch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{
+		"GOOS=linux",
+		"GOARCH=" + arch,
+		"CGO_ENABLED=0", // so the binary works in images without a C library
+	}, nil
+}
+
+var (
+	dockerArchOnce sync.Once
+	dockerArch     string
+	dockerArchErr  error
+)
+
+// dockerServerArch returns the architecture of the Docker daemon, like amd64 or arm64. It only
+// asks the daemon once per process.
+func dockerServerArch(ctx context.Context) (string, error) {
+	dockerArchOnce.Do(func() {
+		cmd := exec.CommandContext(ctx, "docker", "version", "--format", "{{.Server.Arch}}")
+
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+
+		out, err := cmd.Output()
+		if err != nil {
+			dockerArchErr = fmt.Errorf("failed to find the Docker daemon's architecture: %w: %s",
+				err, stderr.Bytes())
+
+			return
+		}
+
+		dockerArch = strings.TrimSpace(string(out))
+	})
+
+	return dockerArch, dockerArchErr
+}
+
+// DescribeTestBinaryBuild describes the command that BuildTestBinary runs on the host and
+// returns the path that the binary will have inside the test service.
+func (c Compose) DescribeTestBinaryBuild(ctx context.Context) (string, string, error) {
+	env, err := testBinaryEnv(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	description := fmt.Sprintf("%s go test -c -o %s",
+		strings.Join(env, " "), filepath.Join(c.testBinaryDir, testBinaryName))
+
+	return description, path.Join(testBinaryTarget, testBinaryName), nil
+}
+
+var (
+	builtTestBinariesMu sync.Mutex
+	builtTestBinaries   = map[string]bool{} // the test binary dirs built by this process
+)
+
+// BuildTestBinary builds the current package's test binary for Linux and returns the binary's
+// path inside the test service.
+//
+// The package can't change while a test process runs, so each process only builds the test
+// binary once, no matter how many tests call docket.
+func (c Compose) BuildTestBinary(ctx context.Context) (string, error) {
+	binaryPath := path.Join(testBinaryTarget, testBinaryName)
+
+	builtTestBinariesMu.Lock()
+	defer builtTestBinariesMu.Unlock()
+
+	if builtTestBinaries[c.testBinaryDir] {
+		return binaryPath, nil
+	}
+
+	env, err := testBinaryEnv(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	defer c.logStep("building test binary", logging.Field{Key: "env", Value: env})()
+
+	tempPath, err := tempbuild.BuildTest(ctx, c.testBinaryDir, testBinaryName+".*.tmp", env)
+	if err != nil {
+		return "", fmt.Errorf("failed to build test binary: %w", err)
+	}
+
+	if err := os.Chmod(tempPath, 0755); err != nil { //nolint:gosec // it's an executable
+		os.Remove(tempPath)
+
+		return "", fmt.Errorf("failed to chmod test binary: %w", err)
+	}
+
+	// Renaming replaces the binary atomically, so a test that's still running the old one
+	// isn't affected.
+	if err := os.Rename(tempPath, filepath.Join(c.testBinaryDir, testBinaryName)); err != nil {
+		os.Remove(tempPath)
+
+		return "", fmt.Errorf("failed to move test binary into place: %w", err)
+	}
+
+	builtTestBinaries[c.testBinaryDir] = true
+
+	return binaryPath, nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func Test_TestBinary(t *testing.T) {
+	suite.Run(t, new(TestBinarySuite))
+}
+
+type TestBinarySuite struct {
+	suite.Suite
+}
+
+func (s *TestBinarySuite) Test_makeTestBinaryDir() {
+	stateDir, err := ioutil.TempDir("", "docket-testbinary-test.")
+	s.Require().NoError(err)
+	defer os.RemoveAll(stateDir)
+
+	s.Require().NoError(os.Setenv("DOCKET_STATE_DIR", stateDir))
+	defer os.Unsetenv("DOCKET_STATE_DIR")
+
+	dir, err := makeTestBinaryDir("app", "/src/a")
+	s.Require().NoError(err)
+	s.Equal(filepath.Join(stateDir, "test-binaries"), filepath.Dir(dir))
+	s.DirExists(dir)
+
+	again, err := makeTestBinaryDir("app", "/src/a")
+	s.Require().NoError(err)
+	s.Equal(dir, again, "runs of the same package should mount the same dir")
+
+	other, err := makeTestBinaryDir("app", "/src/b")
+	s.Require().NoError(err)
+	s.NotEqual(dir, other)
+}
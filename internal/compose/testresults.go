@@ -0,0 +1,130 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TestResult is the result of one test (or subtest) that ran inside the test service.
+type TestResult struct {
+	Name     string
+	Status   string // "pass", "fail", or "skip"
+	Duration time.Duration
+	Output   string // the test's log output
+}
+
+// TestResults parses the output of `go test -v` as it's written and collects the results. It
+// relies on `go test -v` streaming each test's log lines between its "=== RUN" and "--- PASS"
+// lines, which Go has done since 1.14.
+type TestResults struct {
+	mu      sync.Mutex
+	buf     []byte // the current line, until it's complete
+	current string // the test whose output is being written
+	output  map[string]*strings.Builder
+	results []TestResult
+	all     strings.Builder
+}
+
+var (
+	testStartLine  = regexp.MustCompile(`^=== (?:RUN|CONT|PAUSE|NAME)\s+(\S+)`)
+	testResultLine = regexp.MustCompile(`^\s*--- (PASS|FAIL|SKIP): (\S+) \(([0-9.]+)s\)`)
+)
+
+func (r *TestResults) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.all.Write(p)
+	r.buf = append(r.buf, p...)
+
+	for {
+		i := bytes.IndexByte(r.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		r.parseLine(string(r.buf[:i]))
+		r.buf = r.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+func (r *TestResults) parseLine(line string) {
+	if m := testStartLine.FindStringSubmatch(line); m != nil {
+		r.current = m[1]
+
+		return
+	}
+
+	if m := testResultLine.FindStringSubmatch(line); m != nil {
+		seconds, _ := time.ParseDuration(m[3] + "s")
+
+		result := TestResult{
+			Name:     m[2],
+			Status:   strings.ToLower(m[1]),
+			Duration: seconds,
+			Output:   "",
+		}
+		if out, ok := r.output[m[2]]; ok {
+			result.Output = out.String()
+		}
+		r.results = append(r.results, result)
+
+		// Log lines after a subtest's result belong to its parent.
+		r.current = ""
+		if i := strings.LastIndexByte(m[2], '/'); i >= 0 {
+			r.current = m[2][:i]
+		}
+
+		return
+	}
+
+	if r.current == "" || !strings.HasPrefix(line, " ") {
+		return
+	}
+
+	if r.output == nil {
+		r.output = make(map[string]*strings.Builder)
+	}
+	out, ok := r.output[r.current]
+	if !ok {
+		out = &strings.Builder{}
+		r.output[r.current] = out
+	}
+	out.WriteString(strings.TrimSpace(line))
+	out.WriteString("\n")
+}
+
+// Results returns the tests' results in the order they finished.
+func (r *TestResults) Results() []TestResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]TestResult(nil), r.results...)
+}
+
+// Output returns everything that was written.
+func (r *TestResults) Output() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.all.String()
+}
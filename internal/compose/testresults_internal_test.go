@@ -0,0 +1,84 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func Test_TestResults(t *testing.T) {
+	suite.Run(t, new(TestResultsSuite))
+}
+
+type TestResultsSuite struct {
+	suite.Suite
+}
+
+const goTestOutput = `=== RUN   TestA
+    a_test.go:10: starting
+=== RUN   TestA/sub
+    a_test.go:12: inside sub
+    a_test.go:13: bad value
+=== RUN   TestA/skipped
+    a_test.go:20: not today
+    --- FAIL: TestA/sub (0.25s)
+    --- SKIP: TestA/skipped (0.00s)
+    a_test.go:15: after subtests
+--- FAIL: TestA (0.50s)
+=== RUN   TestB
+--- PASS: TestB (1.00s)
+FAIL
+exit status 1
+FAIL	example.com/a	1.503s
+`
+
+func (s *TestResultsSuite) Test_Write() {
+	var results TestResults
+
+	// Write in small pieces to check that lines are put back together.
+	r := strings.NewReader(goTestOutput)
+	buf := make([]byte, 7)
+	for {
+		n, err := r.Read(buf)
+		if err == io.EOF {
+			break
+		}
+		_, err = results.Write(buf[:n])
+		s.Require().NoError(err)
+	}
+
+	s.Equal([]TestResult{
+		{
+			Name:     "TestA/sub",
+			Status:   "fail",
+			Duration: 250 * time.Millisecond,
+			Output:   "a_test.go:12: inside sub\na_test.go:13: bad value\n",
+		},
+		{Name: "TestA/skipped", Status: "skip", Duration: 0, Output: "a_test.go:20: not today\n"},
+		{
+			Name:     "TestA",
+			Status:   "fail",
+			Duration: 500 * time.Millisecond,
+			Output:   "a_test.go:10: starting\na_test.go:15: after subtests\n",
+		},
+		{Name: "TestB", Status: "pass", Duration: time.Second, Output: ""},
+	}, results.Results())
+	s.Equal(goTestOutput, results.Output())
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"sync"
+	"time"
+)
+
+// Phase is how long one phase of a docket run took.
+type Phase struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Timings records how long each phase of a docket run takes. A nil *Timings ignores them.
+type Timings struct {
+	mu     sync.Mutex
+	phases []Phase
+}
+
+// Record records a phase's duration.
+func (t *Timings) Record(name string, d time.Duration) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.phases = append(t.phases, Phase{Name: name, Duration: d})
+}
+
+// Start starts timing a phase and returns a function that records it.
+func (t *Timings) Start(name string) func() {
+	start := time.Now()
+
+	return func() { t.Record(name, time.Since(start)) }
+}
+
+// Phases returns the recorded phases in the order they finished.
+func (t *Timings) Phases() []Phase {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]Phase(nil), t.phases...)
+}
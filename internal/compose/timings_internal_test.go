@@ -0,0 +1,70 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func Test_Timings(t *testing.T) {
+	suite.Run(t, new(TimingsSuite))
+}
+
+type TimingsSuite struct {
+	suite.Suite
+}
+
+func (s *TimingsSuite) Test_Record() {
+	var timings Timings
+	timings.Record("config", time.Second)
+	timings.Record("up", 2*time.Second)
+
+	s.Equal([]Phase{
+		{Name: "config", Duration: time.Second},
+		{Name: "up", Duration: 2 * time.Second},
+	}, timings.Phases())
+}
+
+func (s *TimingsSuite) Test_Start() {
+	var timings Timings
+	record := timings.Start("pull")
+	time.Sleep(10 * time.Millisecond)
+	record()
+
+	phases := timings.Phases()
+	s.Require().Len(phases, 1)
+	s.Equal("pull", phases[0].Name)
+	s.True(phases[0].Duration >= 10*time.Millisecond, phases[0].Duration)
+}
+
+func (s *TimingsSuite) Test_Nil() {
+	var timings *Timings
+	timings.Record("config", time.Second)
+	timings.Start("up")()
+
+	s.Nil(timings.Phases())
+}
+
+func (s *TimingsSuite) Test_logStep() {
+	c := Compose{mode: "full", timings: &Timings{}}
+	c.logStep("down")()
+
+	phases := c.Timings().Phases()
+	s.Require().Len(phases, 1)
+	s.Equal("down", phases[0].Name)
+}
@@ -0,0 +1,226 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// goWorkspaceTarget is where local modules are mounted when a package depends on more than one
+// local module. Each module directory is mounted at the same path it has on the host, but under
+// goWorkspaceTarget, so relative paths between modules (like `replace ../sibling`) still work.
+const goWorkspaceTarget = "/go-workspace"
+
+// goWorkTarget is where docket mounts the go.work file it generates for the container.
+const goWorkTarget = goWorkspaceTarget + "/go.work"
+
+// goWorkspace describes the local modules a package is built with.
+type goWorkspace struct {
+	goWork    string // the host's go.work file, or blank if not in workspace mode
+	goVersion string // the go directive from goWork
+
+	use      []string    // module directories from goWork's use directives
+	replaces []goReplace // goWork's replace directives, with local paths made absolute
+
+	moduleDirs []string // every local module directory that needs to be mounted
+}
+
+// findGoWorkspace returns the package's workspace, or nil if the package only needs its own
+// module directory.
+func findGoWorkspace(ctx context.Context, goList goList) (*goWorkspace, error) {
+	if goList.Module == nil {
+		return nil, nil
+	}
+
+	goWork, err := runGoEnvGOWORK(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var workFile *goWorkFile
+	if goWork != "" {
+		wf, err := runGoWorkEditJSON(ctx, goWork)
+		if err != nil {
+			return nil, err
+		}
+		workFile = &wf
+	}
+
+	readGoMod := func(moduleDir string) (goModFile, error) {
+		return runGoModEditJSON(ctx, filepath.Join(moduleDir, "go.mod"))
+	}
+
+	return makeGoWorkspace(goList.Module.Dir, goWork, workFile, readGoMod)
+}
+
+func makeGoWorkspace(
+	mainModuleDir, goWork string, workFile *goWorkFile,
+	readGoMod func(moduleDir string) (goModFile, error),
+) (*goWorkspace, error) {
+	ws := &goWorkspace{
+		goWork:     goWork,
+		goVersion:  "",
+		use:        nil,
+		replaces:   nil,
+		moduleDirs: []string{mainModuleDir},
+	}
+
+	// Outside of workspace mode, only the main module's replace directives apply. Inside, the
+	// replace directives of every workspace module apply.
+	mainModules := []string{mainModuleDir}
+
+	if workFile != nil {
+		ws.goVersion = workFile.Go
+		workDir := filepath.Dir(goWork)
+
+		for _, use := range workFile.Use {
+			dir := absFrom(workDir, use.DiskPath)
+			ws.use = append(ws.use, dir)
+			mainModules = append(mainModules, dir)
+			ws.moduleDirs = append(ws.moduleDirs, dir)
+		}
+
+		for _, r := range workFile.Replace {
+			if r.isLocal() {
+				r.New.Path = absFrom(workDir, r.New.Path)
+				ws.moduleDirs = append(ws.moduleDirs, r.New.Path)
+			}
+			ws.replaces = append(ws.replaces, r)
+		}
+	}
+
+	for _, dir := range uniqueGOPATHEntries(mainModules) {
+		goMod, err := readGoMod(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range goMod.Replace {
+			if r.isLocal() {
+				ws.moduleDirs = append(ws.moduleDirs, absFrom(dir, r.New.Path))
+			}
+		}
+	}
+
+	ws.moduleDirs = uniqueGOPATHEntries(ws.moduleDirs)
+
+	if ws.goWork == "" && len(ws.moduleDirs) == 1 {
+		return nil, nil
+	}
+
+	return ws, nil
+}
+
+func absFrom(dir, p string) string {
+	if filepath.IsAbs(p) {
+		return filepath.Clean(p)
+	}
+
+	return filepath.Join(dir, p)
+}
+
+// workspacePath returns the path inside the container where a host directory is mounted.
+func workspacePath(hostDir string) string {
+	p := strings.TrimPrefix(hostDir, filepath.VolumeName(hostDir))
+
+	return path.Join(goWorkspaceTarget, filepath.ToSlash(p))
+}
+
+// mountsForWorkspace mounts the module cache from the first GOPATH entry and every local module
+// in the workspace.
+func mountsForWorkspace(goList goList, goPath []string, ws goWorkspace) (sourceMounts, error) {
+	const goPathTarget = "/go"
+
+	goPath = uniqueGOPATHEntries(goPath)
+	if len(goPath) == 0 {
+		return sourceMounts{}, errEmptyGOPATH
+	}
+
+	mounts := sourceMounts{
+		volumes: []cmpVolume{
+			{
+				Type:   "bind",
+				Source: filepath.Join(goPath[0], "pkg", "mod"),
+				Target: fmt.Sprintf("%s/pkg/mod", goPathTarget),
+			},
+		},
+		workingDir:  workspacePath(goList.Dir),
+		environment: nil,
+	}
+
+	for _, dir := range ws.moduleDirs {
+		mounts.volumes = append(mounts.volumes, cmpVolume{
+			Type:   "bind",
+			Source: dir,
+			Target: workspacePath(dir),
+		})
+	}
+
+	return mounts, nil
+}
+
+// makeGoWorkFile makes a go.work file that refers to the workspace's modules where they are
+// mounted inside the container.
+func makeGoWorkFile(ws goWorkspace) []byte {
+	var buf bytes.Buffer
+
+	if ws.goVersion != "" {
+		fmt.Fprintf(&buf, "go %s\n\n", ws.goVersion)
+	}
+
+	fmt.Fprintf(&buf, "use (\n")
+	for _, dir := range ws.use {
+		fmt.Fprintf(&buf, "\t%s\n", quoteModPath(workspacePath(dir)))
+	}
+	fmt.Fprintf(&buf, ")\n")
+
+	if len(ws.replaces) > 0 {
+		fmt.Fprintf(&buf, "\nreplace (\n")
+		for _, r := range ws.replaces {
+			newPath := r.New.Path
+			if r.isLocal() {
+				newPath = quoteModPath(workspacePath(newPath))
+			}
+			fmt.Fprintf(&buf, "\t%s => %s\n", joinModVersion(r.Old.Path, r.Old.Version),
+				joinModVersion(newPath, r.New.Version))
+		}
+		fmt.Fprintf(&buf, ")\n")
+	}
+
+	return buf.Bytes()
+}
+
+// quoteModPath quotes a path for a go.work file if it contains characters that need quoting.
+func quoteModPath(p string) string {
+	if strings.ContainsAny(p, " \t\"'`\\") {
+		return strconv.Quote(p)
+	}
+
+	return p
+}
+
+func joinModVersion(modPath, version string) string {
+	if version == "" {
+		return modPath
+	}
+
+	return modPath + " " + version
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compose
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func Test_Workspace(t *testing.T) {
+	suite.Run(t, new(WorkspaceSuite))
+}
+
+type WorkspaceSuite struct {
+	suite.Suite
+}
+
+func (s *WorkspaceSuite) readGoMod(goMods map[string]goModFile) func(string) (goModFile, error) {
+	return func(dir string) (goModFile, error) {
+		return goMods[filepath.ToSlash(dir)], nil
+	}
+}
+
+func (s *WorkspaceSuite) Test_makeGoWorkspace_NoWorkspace() {
+	ws, err := makeGoWorkspace(filepath.FromSlash("/src/a"), "", nil,
+		s.readGoMod(map[string]goModFile{}))
+
+	s.NoError(err)
+	s.Nil(ws)
+}
+
+func (s *WorkspaceSuite) Test_makeGoWorkspace_LocalReplace() {
+	goMods := map[string]goModFile{
+		"/src/a": {
+			Go: "1.18",
+			Replace: []goReplace{
+				{Old: goModVersion{Path: "example.com/b", Version: ""}, New: goModVersion{Path: "../b", Version: ""}},
+				{Old: goModVersion{Path: "example.com/c", Version: ""}, New: goModVersion{Path: "example.com/d", Version: "v1.0.0"}},
+			},
+		},
+	}
+
+	ws, err := makeGoWorkspace(filepath.FromSlash("/src/a"), "", nil, s.readGoMod(goMods))
+	s.Require().NoError(err)
+	s.Require().NotNil(ws)
+
+	s.Equal("", ws.goWork)
+	s.Equal([]string{filepath.FromSlash("/src/a"), filepath.FromSlash("/src/b")}, ws.moduleDirs)
+}
+
+func (s *WorkspaceSuite) Test_makeGoWorkspace_GoWork() {
+	workFile := &goWorkFile{
+		Go: "1.21",
+		Use: []struct{ DiskPath string }{
+			{DiskPath: "./a"},
+			{DiskPath: "./b"},
+		},
+		Replace: []goReplace{
+			{Old: goModVersion{Path: "example.com/c", Version: ""}, New: goModVersion{Path: "./c", Version: ""}},
+		},
+	}
+	goMods := map[string]goModFile{
+		"/ws/b": {
+			Go: "1.21",
+			Replace: []goReplace{
+				{Old: goModVersion{Path: "example.com/d", Version: ""}, New: goModVersion{Path: "/elsewhere/d", Version: ""}},
+			},
+		},
+	}
+
+	ws, err := makeGoWorkspace(filepath.FromSlash("/ws/a"), filepath.FromSlash("/ws/go.work"), workFile,
+		s.readGoMod(goMods))
+	s.Require().NoError(err)
+	s.Require().NotNil(ws)
+
+	s.Equal([]string{filepath.FromSlash("/ws/a"), filepath.FromSlash("/ws/b")}, ws.use)
+	s.Equal([]string{
+		filepath.FromSlash("/ws/a"),
+		filepath.FromSlash("/ws/b"),
+		filepath.FromSlash("/ws/c"),
+		filepath.FromSlash("/elsewhere/d"),
+	}, ws.moduleDirs)
+
+	s.Equal(`go 1.21
+
+use (
+	/go-workspace/ws/a
+	/go-workspace/ws/b
+)
+
+replace (
+	example.com/c => /go-workspace/ws/c
+)
+`, string(makeGoWorkFile(*ws)))
+}
+
+func (s *WorkspaceSuite) Test_mountsForWorkspace() {
+	gl := goList{Dir: filepath.FromSlash("/ws/a/pkg"), ImportPath: "", Module: nil}
+	ws := goWorkspace{
+		goWork:     "",
+		goVersion:  "",
+		use:        nil,
+		replaces:   nil,
+		moduleDirs: []string{filepath.FromSlash("/ws/a"), filepath.FromSlash("/ws/b")},
+	}
+
+	mounts, err := mountsForWorkspace(gl, []string{filepath.FromSlash("/go")}, ws)
+	s.Require().NoError(err)
+
+	s.Equal([]cmpVolume{
+		{Type: "bind", Source: filepath.FromSlash("/go/pkg/mod"), Target: "/go/pkg/mod"},
+		{Type: "bind", Source: filepath.FromSlash("/ws/a"), Target: "/go-workspace/ws/a"},
+		{Type: "bind", Source: filepath.FromSlash("/ws/b"), Target: "/go-workspace/ws/b"},
+	}, mounts.volumes)
+	s.Equal("/go-workspace/ws/a/pkg", mounts.workingDir)
+}
+
+func (s *WorkspaceSuite) Test_quoteModPath() {
+	s.Equal("/go-workspace/plain", quoteModPath("/go-workspace/plain"))
+	s.Equal(`"/go-workspace/with space"`, quoteModPath("/go-workspace/with space"))
+}
+
+func (s *WorkspaceSuite) Test_writeGoWorkFile() {
+	stateDir, err := ioutil.TempDir("", "docket-workspace-test.")
+	s.Require().NoError(err)
+	defer os.RemoveAll(stateDir)
+
+	s.Require().NoError(os.Setenv("DOCKET_STATE_DIR", stateDir))
+	defer os.Unsetenv("DOCKET_STATE_DIR")
+
+	ws := goWorkspace{
+		goWork:     filepath.FromSlash("/ws/go.work"),
+		goVersion:  "1.18",
+		use:        []string{filepath.FromSlash("/ws/a")},
+		replaces:   nil,
+		moduleDirs: []string{filepath.FromSlash("/ws/a")},
+	}
+
+	path, err := writeGoWorkFile("app", ws)
+	s.Require().NoError(err)
+	s.FileExists(path)
+
+	info, err := os.Stat(path)
+	s.Require().NoError(err)
+
+	again, err := writeGoWorkFile("app", ws)
+	s.Require().NoError(err)
+	s.Equal(path, again, "runs with the same workspace should mount the same file")
+
+	infoAgain, err := os.Stat(again)
+	s.Require().NoError(err)
+	s.True(os.SameFile(info, infoAgain), "an unchanged file shouldn't be rewritten")
+
+	ws.use = append(ws.use, filepath.FromSlash("/ws/b"))
+	changed, err := writeGoWorkFile("app", ws)
+	s.Require().NoError(err)
+	s.Equal(path, changed)
+
+	data, err := ioutil.ReadFile(changed)
+	s.Require().NoError(err)
+	s.Equal(string(makeGoWorkFile(ws)), string(data))
+
+	other, err := writeGoWorkFile("other", ws)
+	s.Require().NoError(err)
+	s.NotEqual(path, other)
+}
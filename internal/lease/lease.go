@@ -0,0 +1,216 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lease lets processes share a docker-compose project.
+//
+// `go test ./...` tests packages in parallel processes, and several of them can use the same
+// project. Each process holds a lease on the project while it uses it. A lock file
+// serializes bringing the project up and taking it down, and the holder files count the
+// processes using it, so that only the last one takes it down.
+//
+// The files live in a directory for each project:
+//
+//     lock             exists while a process holds the lock; contains its pid
+//     holders/PID.N    one for each lease held by process PID
+//
+// A process that exits without releasing its lease or the lock leaves stale files behind.
+// Since the files name their process, the next process to take the lock removes them.
+package lease
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/bloomberg/docket/internal/statedir"
+)
+
+// pollInterval is how often Acquire and Release check whether the lock is free.
+var pollInterval = 100 * time.Millisecond
+
+// staleUnreadableLock is how old a lock file that doesn't contain a pid has to be before it's
+// considered stale. (The process that created it might not have written its pid yet.)
+const staleUnreadableLock = 10 * time.Second
+
+// holderCount distinguishes the leases held by one process.
+var holderCount int32
+
+// Lease is one holder's share of a project.
+type Lease struct {
+	dir    string
+	holder string // the path of the holder file
+}
+
+var unsafeProjectChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// Dir returns the directory that holds the lease files for a project.
+func Dir(project string) (string, error) {
+	return statedir.Dir("projects", unsafeProjectChars.ReplaceAllString(project, "_"))
+}
+
+// Acquire takes the lock in dir, calls setup, adds a holder, and releases the lock.
+//
+// If setup fails, Acquire doesn't add a holder and returns setup's error.
+func Acquire(ctx context.Context, dir string, setup func() error) (*Lease, error) {
+	holdersDir := filepath.Join(dir, "holders")
+	if err := os.MkdirAll(holdersDir, 0755); err != nil { //nolint:gosec // not secret
+		return nil, fmt.Errorf("failed to create lease dir: %w", err)
+	}
+
+	unlock, err := lock(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := setup(); err != nil {
+		return nil, err
+	}
+
+	n := atomic.AddInt32(&holderCount, 1)
+	holder := filepath.Join(holdersDir, fmt.Sprintf("%d.%d", os.Getpid(), n))
+	if err := ioutil.WriteFile(holder, nil, 0644); err != nil { //nolint:gosec // not secret
+		return nil, fmt.Errorf("failed to add lease holder: %w", err)
+	}
+
+	return &Lease{dir: dir, holder: holder}, nil
+}
+
+// Release takes the lock, removes the lease's holder and any stale holders, calls teardown if
+// no holders are left, and releases the lock. It returns the number of other holders.
+func (l *Lease) Release(ctx context.Context, teardown func() error) (int, error) {
+	unlock, err := lock(ctx, l.dir)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	if err := os.Remove(l.holder); err != nil && !os.IsNotExist(err) {
+		return 0, fmt.Errorf("failed to remove lease holder: %w", err)
+	}
+
+	holders, err := Holders(l.dir)
+	if err != nil {
+		return 0, err
+	}
+	if len(holders) > 0 {
+		return len(holders), nil
+	}
+
+	return 0, teardown()
+}
+
+// Holders removes the stale holders in dir and returns the pids of the processes holding the
+// remaining leases. A pid appears once for each lease its process holds.
+func Holders(dir string) ([]int, error) {
+	holdersDir := filepath.Join(dir, "holders")
+
+	infos, err := ioutil.ReadDir(holdersDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read lease holders: %w", err)
+	}
+
+	var pids []int
+	for _, info := range infos {
+		pid, err := strconv.Atoi(strings.SplitN(info.Name(), ".", 2)[0])
+		if err == nil && ProcessAlive(pid) {
+			pids = append(pids, pid)
+
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(holdersDir, info.Name())); err != nil {
+			return nil, fmt.Errorf("failed to remove stale lease holder: %w", err)
+		}
+	}
+
+	return pids, nil
+}
+
+// lock waits until it can create the lock file in dir and returns a function that removes it.
+func lock(ctx context.Context, dir string) (func(), error) {
+	path := filepath.Join(dir, "lock")
+	pid := os.Getpid()
+
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644) //nolint:gosec // only a pid
+		if err == nil {
+			_, err = fmt.Fprintf(f, "%d\n", pid)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(path)
+
+				return nil, fmt.Errorf("failed to write lock: %w", err)
+			}
+
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create lock: %w", err)
+		}
+
+		if removeStaleLock(path) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed waiting for lock %s: %w", path, ctx.Err())
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// removeStaleLock removes the lock file at path if the process that created it is gone.
+func removeStaleLock(path string) bool {
+	pid, err := readLockPid(path)
+	switch {
+	case os.IsNotExist(err):
+		return true // it was just removed
+	case err != nil:
+		info, statErr := os.Stat(path)
+		if statErr != nil || time.Since(info.ModTime()) < staleUnreadableLock {
+			return false
+		}
+	case ProcessAlive(pid):
+		return false
+	}
+
+	// Check again right before removing it, in case another process already replaced it.
+	if newPid, newErr := readLockPid(path); newPid != pid || (newErr == nil) != (err == nil) {
+		return false
+	}
+
+	return os.Remove(path) == nil
+}
+
+func readLockPid(path string) (int, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(strings.TrimSpace(string(b)))
+}
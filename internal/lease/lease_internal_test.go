@@ -0,0 +1,240 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func Test_Lease(t *testing.T) {
+	suite.Run(t, new(LeaseSuite))
+}
+
+type LeaseSuite struct {
+	suite.Suite
+
+	dir string
+}
+
+func (s *LeaseSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "docket-lease-test.")
+	s.Require().NoError(err)
+	s.dir = dir
+}
+
+func (s *LeaseSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+// deadPid returns the pid of a process that has exited.
+func (s *LeaseSuite) deadPid() int {
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	s.Require().NoError(cmd.Run())
+
+	return cmd.ProcessState.Pid()
+}
+
+func noop() error { return nil }
+
+func (s *LeaseSuite) Test_LastHolderTearsDown() {
+	ctx := context.Background()
+
+	setups := 0
+	setup := func() error {
+		setups++
+
+		return nil
+	}
+
+	first, err := Acquire(ctx, s.dir, setup)
+	s.Require().NoError(err)
+	second, err := Acquire(ctx, s.dir, setup)
+	s.Require().NoError(err)
+	s.Equal(2, setups)
+
+	holders, err := Holders(s.dir)
+	s.NoError(err)
+	s.Equal([]int{os.Getpid(), os.Getpid()}, holders)
+
+	teardowns := 0
+	teardown := func() error {
+		teardowns++
+
+		return nil
+	}
+
+	others, err := first.Release(ctx, teardown)
+	s.NoError(err)
+	s.Equal(1, others)
+	s.Zero(teardowns)
+
+	others, err = second.Release(ctx, teardown)
+	s.NoError(err)
+	s.Zero(others)
+	s.Equal(1, teardowns)
+
+	_, err = os.Stat(filepath.Join(s.dir, "lock"))
+	s.True(os.IsNotExist(err), "lock should be released")
+}
+
+func (s *LeaseSuite) Test_SetupFails() {
+	ctx := context.Background()
+	errSetup := errors.New("setup failed")
+
+	_, err := Acquire(ctx, s.dir, func() error { return errSetup })
+	s.True(errors.Is(err, errSetup), err)
+
+	holders, err := Holders(s.dir)
+	s.NoError(err)
+	s.Empty(holders)
+
+	_, err = os.Stat(filepath.Join(s.dir, "lock"))
+	s.True(os.IsNotExist(err), "lock should be released")
+}
+
+func (s *LeaseSuite) Test_TeardownFails() {
+	ctx := context.Background()
+	errTeardown := errors.New("teardown failed")
+
+	l, err := Acquire(ctx, s.dir, noop)
+	s.Require().NoError(err)
+
+	_, err = l.Release(ctx, func() error { return errTeardown })
+	s.True(errors.Is(err, errTeardown), err)
+}
+
+func (s *LeaseSuite) Test_StaleHolder() {
+	ctx := context.Background()
+
+	holdersDir := filepath.Join(s.dir, "holders")
+	s.Require().NoError(os.MkdirAll(holdersDir, 0755))
+	stale := filepath.Join(holdersDir, fmt.Sprintf("%d.1", s.deadPid()))
+	s.Require().NoError(ioutil.WriteFile(stale, nil, 0600))
+
+	l, err := Acquire(ctx, s.dir, noop)
+	s.Require().NoError(err)
+
+	teardowns := 0
+	others, err := l.Release(ctx, func() error {
+		teardowns++
+
+		return nil
+	})
+	s.NoError(err)
+	s.Zero(others)
+	s.Equal(1, teardowns)
+
+	_, err = os.Stat(stale)
+	s.True(os.IsNotExist(err), "stale holder should be removed")
+}
+
+func (s *LeaseSuite) Test_StaleLock() {
+	lockPath := filepath.Join(s.dir, "lock")
+	s.Require().NoError(ioutil.WriteFile(lockPath, []byte(fmt.Sprintf("%d\n", s.deadPid())), 0600))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := Acquire(ctx, s.dir, noop)
+	s.NoError(err)
+}
+
+func (s *LeaseSuite) Test_HeldLock() {
+	lockPath := filepath.Join(s.dir, "lock")
+	s.Require().NoError(ioutil.WriteFile(lockPath, []byte(fmt.Sprintf("%d\n", os.Getpid())), 0600))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*pollInterval)
+	defer cancel()
+
+	_, err := Acquire(ctx, s.dir, noop)
+	s.True(errors.Is(err, context.DeadlineExceeded), err)
+}
+
+func (s *LeaseSuite) Test_SerializesSetup() {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	active, maxActive := 0, 0
+	setup := func() error {
+		mu.Lock()
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+
+		return nil
+	}
+
+	const n = 5
+	leases := make([]*Lease, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			leases[i], errs[i] = Acquire(ctx, s.dir, setup)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		s.Require().NoError(err)
+	}
+	s.Equal(1, maxActive)
+
+	teardowns := 0
+	for _, l := range leases {
+		_, err := l.Release(ctx, func() error {
+			teardowns++
+
+			return nil
+		})
+		s.NoError(err)
+	}
+	s.Equal(1, teardowns)
+}
+
+func (s *LeaseSuite) Test_Dir() {
+	s.Require().NoError(os.Setenv("DOCKET_STATE_DIR", s.dir))
+	defer os.Unsetenv("DOCKET_STATE_DIR")
+
+	dir, err := Dir("my/project")
+	s.Require().NoError(err)
+	s.Equal(filepath.Join(s.dir, "projects", "my_project"), dir)
+
+	info, err := os.Stat(dir)
+	s.Require().NoError(err)
+	s.True(info.IsDir())
+}
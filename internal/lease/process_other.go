@@ -1,4 +1,4 @@
-// Copyright 2019 Bloomberg Finance L.P.
+// Copyright 2026 Bloomberg Finance L.P.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -12,16 +12,18 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package compose
+// +build !windows
 
-import (
-	"os"
+package lease
 
-	"github.com/fatih/color"
+import (
+	"errors"
+	"syscall"
 )
 
-func tracef(format string, a ...interface{}) {
-	fprintfGreen := color.New(color.FgBlue).FprintfFunc()
-	fprintfGreen(os.Stderr, "[docket] ")
-	fprintfGreen(os.Stderr, format, a...)
+// ProcessAlive reports whether a process exists. (Signal 0 only checks.)
+func ProcessAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+
+	return err == nil || errors.Is(err, syscall.EPERM)
 }
@@ -0,0 +1,156 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// NewText returns a Logger that writes messages at level or above as colored lines of text,
+// like
+//
+//     [docket] up mode=full project=app command=[docker-compose --file docket.yaml up -d]
+func NewText(w io.Writer, level Level) Logger {
+	return textLogger{w: w, level: level}
+}
+
+type textLogger struct {
+	w     io.Writer
+	level Level
+}
+
+var levelColors = map[Level]*color.Color{
+	LevelDebug: color.New(color.FgCyan),
+	LevelInfo:  color.New(color.FgBlue),
+	LevelWarn:  color.New(color.FgYellow),
+	LevelError: color.New(color.FgRed),
+}
+
+func (l textLogger) Log(level Level, msg string, fields ...Field) {
+	if level < l.level {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("[docket] ")
+	if level != LevelInfo {
+		b.WriteString(strings.ToUpper(level.String()) + ": ")
+	}
+	b.WriteString(msg)
+
+	for _, f := range fields {
+		fmt.Fprintf(&b, " %s=%s", f.Key, formatTextValue(f.Value))
+	}
+
+	c, ok := levelColors[level]
+	if !ok {
+		c = levelColors[LevelInfo]
+	}
+
+	// Write the whole line at once so that lines from concurrent tests don't interleave.
+	_, _ = io.WriteString(l.w, c.Sprint(b.String())+"\n")
+}
+
+func formatTextValue(v interface{}) string {
+	var s string
+	switch v := v.(type) {
+	case time.Duration:
+		return v.Round(time.Millisecond).String()
+	case []string:
+		return fmt.Sprintf("%v", v)
+	case error:
+		s = v.Error()
+	case string:
+		s = v
+	default:
+		s = fmt.Sprintf("%v", v)
+	}
+
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
+
+// NewJSON returns a Logger that writes messages at level or above as JSON objects, one per
+// line, like
+//
+//     {"time":"2020-01-02T03:04:05.678Z","level":"info","msg":"up finished","duration":1.5}
+//
+// Durations are in seconds.
+func NewJSON(w io.Writer, level Level) Logger {
+	return jsonLogger{w: w, level: level, now: time.Now}
+}
+
+type jsonLogger struct {
+	w     io.Writer
+	level Level
+	now   func() time.Time
+}
+
+func (l jsonLogger) Log(level Level, msg string, fields ...Field) {
+	if level < l.level {
+		return
+	}
+
+	var b bytes.Buffer
+	b.WriteString("{")
+	writeJSONField(&b, "time", l.now().UTC().Format(time.RFC3339Nano))
+	b.WriteString(",")
+	writeJSONField(&b, "level", level.String())
+	b.WriteString(",")
+	writeJSONField(&b, "msg", msg)
+
+	for _, f := range fields {
+		b.WriteString(",")
+		writeJSONField(&b, f.Key, jsonValue(f.Value))
+	}
+	b.WriteString("}\n")
+
+	_, _ = l.w.Write(b.Bytes())
+}
+
+func writeJSONField(b *bytes.Buffer, key string, value interface{}) {
+	k, _ := json.Marshal(key)
+
+	v, err := json.Marshal(value)
+	if err != nil {
+		v, _ = json.Marshal(fmt.Sprintf("%v", value))
+	}
+
+	b.Write(k)
+	b.WriteString(":")
+	b.Write(v)
+}
+
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case time.Duration:
+		return v.Seconds()
+	case error:
+		return v.Error()
+	default:
+		return v
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logging is docket's leveled, structured logging.
+//
+// Docket logs through the Logger from Get. Programs (and tests) can replace it with Set.
+// Otherwise, DOCKET_LOG and DOCKET_LOG_LEVEL choose the format and level:
+//
+//     DOCKET_LOG=text (default)    [docket] up mode=full project=app command=[docker-compose ...]
+//     DOCKET_LOG=json              {"time":"...","level":"info","msg":"up","mode":"full",...}
+//     DOCKET_LOG=quiet             nothing
+//
+//     DOCKET_LOG_LEVEL=debug|info (default)|warn|error
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the importance of a log message.
+type Level int
+
+// Levels, from least to most important.
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+}
+
+// ParseLevel parses the name of a level.
+func ParseLevel(s string) (Level, error) {
+	for l := LevelDebug; l <= LevelError; l++ {
+		if strings.EqualFold(s, l.String()) {
+			return l, nil
+		}
+	}
+
+	return LevelInfo, fmt.Errorf("unknown log level %q", s)
+}
+
+// Field is a structured attribute of a log message.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// The keys of the fields docket uses.
+const (
+	KeyCommand  = "command"
+	KeyDuration = "duration"
+	KeyError    = "error"
+	KeyFile     = "file"
+	KeyImage    = "image"
+	KeyMode     = "mode"
+	KeyOutput   = "output"
+	KeyProject  = "project"
+	KeyService  = "service"
+)
+
+// Command is the field for a command's arguments.
+func Command(args []string) Field { return Field{Key: KeyCommand, Value: args} }
+
+// Duration is the field for how long something took.
+func Duration(d time.Duration) Field { return Field{Key: KeyDuration, Value: d} }
+
+// Error is the field for an error.
+func Error(err error) Field { return Field{Key: KeyError, Value: err} }
+
+// File is the field for a file's path.
+func File(path string) Field { return Field{Key: KeyFile, Value: path} }
+
+// Image is the field for an image reference.
+func Image(image string) Field { return Field{Key: KeyImage, Value: image} }
+
+// Mode is the field for the docket mode.
+func Mode(mode string) Field { return Field{Key: KeyMode, Value: mode} }
+
+// Output is the field for a line of a command's output.
+func Output(line string) Field { return Field{Key: KeyOutput, Value: line} }
+
+// Project is the field for the docker-compose project's name.
+func Project(project string) Field { return Field{Key: KeyProject, Value: project} }
+
+// Service is the field for a docker-compose service's name.
+func Service(service string) Field { return Field{Key: KeyService, Value: service} }
+
+// Logger receives docket's log messages.
+type Logger interface {
+	Log(level Level, msg string, fields ...Field)
+}
+
+// LoggerFunc lets a function be a Logger.
+type LoggerFunc func(level Level, msg string, fields ...Field)
+
+// Log calls f.
+func (f LoggerFunc) Log(level Level, msg string, fields ...Field) {
+	f(level, msg, fields...)
+}
+
+// Nop returns a Logger that discards everything.
+func Nop() Logger {
+	return LoggerFunc(func(Level, string, ...Field) {})
+}
+
+var (
+	mu        sync.RWMutex
+	logger    Logger // nil means to use the environment
+	envLogger Logger // the Logger for the environment, made the first time Get needs it
+)
+
+// envOutput is where the Logger for the environment writes. It's a variable so that tests can
+// replace it.
+var envOutput io.Writer = os.Stderr
+
+// Set replaces the Logger that Get returns. Setting nil goes back to the Logger that
+// DOCKET_LOG and DOCKET_LOG_LEVEL choose, which is made again the next time Get needs it.
+func Set(l Logger) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	logger = l
+	if l == nil {
+		envLogger = nil
+	}
+}
+
+// Get returns the Logger that docket should use.
+func Get() Logger {
+	mu.RLock()
+	l := logger
+	if l == nil {
+		l = envLogger
+	}
+	mu.RUnlock()
+
+	if l != nil {
+		return l
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if logger != nil {
+		return logger
+	}
+
+	// Making the Logger once means that warnings about bad values are only logged once.
+	if envLogger == nil {
+		envLogger = fromEnv(envOutput, os.Getenv("DOCKET_LOG"), os.Getenv("DOCKET_LOG_LEVEL"))
+	}
+
+	return envLogger
+}
+
+// fromEnv makes the Logger for the values of DOCKET_LOG and DOCKET_LOG_LEVEL. Since docket
+// can't return an error from here, unknown values fall back to the defaults, with a warning.
+func fromEnv(w io.Writer, format, levelName string) Logger {
+	level := LevelInfo
+	var warnings []string
+
+	if levelName != "" {
+		var err error
+		if level, err = ParseLevel(levelName); err != nil {
+			warnings = append(warnings, fmt.Sprintf("ignoring DOCKET_LOG_LEVEL: %v", err))
+		}
+	}
+
+	var l Logger
+	switch strings.ToLower(format) {
+	case "", "text":
+		l = NewText(w, level)
+	case "json":
+		l = NewJSON(w, level)
+	case "quiet":
+		return Nop()
+	default:
+		l = NewText(w, level)
+		warnings = append(warnings, fmt.Sprintf("ignoring unknown DOCKET_LOG %q", format))
+	}
+
+	for _, warning := range warnings {
+		l.Log(LevelWarn, warning)
+	}
+
+	return l
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/suite"
+)
+
+func Test_Logging(t *testing.T) {
+	suite.Run(t, new(LoggingSuite))
+}
+
+type LoggingSuite struct {
+	suite.Suite
+
+	noColor bool
+}
+
+func (s *LoggingSuite) SetupTest() {
+	s.noColor = color.NoColor
+	color.NoColor = true
+}
+
+func (s *LoggingSuite) TearDownTest() {
+	color.NoColor = s.noColor
+	Set(nil)
+}
+
+func (s *LoggingSuite) Test_Text() {
+	var b strings.Builder
+	l := NewText(&b, LevelInfo)
+
+	l.Log(LevelDebug, "hidden")
+	l.Log(LevelInfo, "up", Mode("full"), Command([]string{"docker-compose", "up", "-d"}))
+	l.Log(LevelInfo, "up finished", Duration(1500*time.Millisecond))
+	l.Log(LevelWarn, "careful", File("a b.yaml"), Error(errors.New("oops")))
+
+	s.Equal(`[docket] up mode=full command=[docker-compose up -d]
+[docket] up finished duration=1.5s
+[docket] WARN: careful file="a b.yaml" error=oops
+`, b.String())
+}
+
+func (s *LoggingSuite) Test_JSON() {
+	var b strings.Builder
+	l := jsonLogger{
+		w:     &b,
+		level: LevelDebug,
+		now:   func() time.Time { return time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC) },
+	}
+
+	l.Log(LevelDebug, "up finished", Service("redis"), Duration(1500*time.Millisecond))
+	l.Log(LevelError, "failed", Error(errors.New("oops")), Command([]string{"a", "b"}))
+
+	s.Equal(`{"time":"2020-01-02T03:04:05Z","level":"debug","msg":"up finished",`+
+		`"service":"redis","duration":1.5}
+{"time":"2020-01-02T03:04:05Z","level":"error","msg":"failed","error":"oops",`+
+		`"command":["a","b"]}
+`, b.String())
+}
+
+func (s *LoggingSuite) Test_fromEnv() {
+	testCases := []struct {
+		format, level string
+		want          string
+	}{
+		{"", "", "[docket] info\n[docket] WARN: warn\n"},
+		{"text", "warn", "[docket] WARN: warn\n"},
+		{"TEXT", "DEBUG", "[docket] DEBUG: debug\n[docket] info\n[docket] WARN: warn\n"},
+		{"quiet", "debug", ""},
+		{"xml", "", "[docket] WARN: ignoring unknown DOCKET_LOG \"xml\"\n" +
+			"[docket] info\n[docket] WARN: warn\n"},
+		{"", "loud", "[docket] WARN: ignoring DOCKET_LOG_LEVEL: unknown log level \"loud\"\n" +
+			"[docket] info\n[docket] WARN: warn\n"},
+	}
+
+	for _, tc := range testCases {
+		var b strings.Builder
+		l := fromEnv(&b, tc.format, tc.level)
+		l.Log(LevelDebug, "debug")
+		l.Log(LevelInfo, "info")
+		l.Log(LevelWarn, "warn")
+
+		s.Equal(tc.want, b.String(), "format=%q level=%q", tc.format, tc.level)
+	}
+
+	var b strings.Builder
+	fromEnv(&b, "json", "").Log(LevelInfo, "info")
+	s.Contains(b.String(), `"msg":"info"`)
+}
+
+func (s *LoggingSuite) Test_Set() {
+	var got []string
+	Set(LoggerFunc(func(level Level, msg string, fields ...Field) {
+		got = append(got, level.String()+" "+msg)
+	}))
+
+	Get().Log(LevelWarn, "hello")
+	s.Equal([]string{"warn hello"}, got)
+
+	Set(nil)
+	_, isText := Get().(textLogger)
+	s.True(isText)
+}
+
+func (s *LoggingSuite) Test_Get_FromEnvOnce() {
+	var b strings.Builder
+	oldOutput := envOutput
+	envOutput = &b
+	defer func() { envOutput = oldOutput }()
+
+	s.Require().NoError(os.Setenv("DOCKET_LOG", "bogus"))
+	defer os.Unsetenv("DOCKET_LOG")
+
+	Set(nil)
+	Get().Log(LevelInfo, "one")
+	Get().Log(LevelInfo, "two")
+
+	s.Equal(`[docket] WARN: ignoring unknown DOCKET_LOG "bogus"
+[docket] one
+[docket] two
+`, b.String())
+
+	b.Reset()
+	Set(nil)
+	Get().Log(LevelInfo, "three")
+
+	s.Equal(`[docket] WARN: ignoring unknown DOCKET_LOG "bogus"
+[docket] three
+`, b.String())
+}
+
+func (s *LoggingSuite) Test_ParseLevel() {
+	level, err := ParseLevel("Error")
+	s.NoError(err)
+	s.Equal(LevelError, level)
+
+	_, err = ParseLevel("verbose")
+	s.Error(err)
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package statedir finds the directory where docket keeps state that outlives a process.
+package statedir
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Dir returns a directory for docket's state, creating it if necessary. The elements are
+// joined onto the base directory, which is $DOCKET_STATE_DIR if that's set, a docket
+// directory in the user's cache directory otherwise, or a docket directory in the default
+// directory for temporary files if there's no user cache directory.
+func Dir(elem ...string) (string, error) {
+	base := os.Getenv("DOCKET_STATE_DIR")
+	if base == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			cacheDir = os.TempDir()
+		}
+		base = filepath.Join(cacheDir, "docket", "state")
+	}
+
+	dir := filepath.Join(append([]string{base}, elem...)...)
+	if err := os.MkdirAll(dir, 0755); err != nil { //nolint:gosec // not secret
+		return "", fmt.Errorf("failed to create state dir: %w", err)
+	}
+
+	return dir, nil
+}
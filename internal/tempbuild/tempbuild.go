@@ -27,7 +27,15 @@ import (
 // Instead of installing a program in a global location like GOBIN, you can use Build
 // to make a temporary/private copy of the program.
 func Build(ctx context.Context, packageSpec, tempFilePattern string) (string, error) {
-	file, err := ioutil.TempFile("", tempFilePattern)
+	return BuildInDir(ctx, "", packageSpec, tempFilePattern)
+}
+
+// BuildInDir builds a package at a temporary location inside dir. If dir is empty, it uses
+// the default directory for temporary files.
+//
+// Building next to where the program will end up lets you os.Rename it into place atomically.
+func BuildInDir(ctx context.Context, dir, packageSpec, tempFilePattern string) (string, error) {
+	file, err := ioutil.TempFile(dir, tempFilePattern)
 	if err != nil {
 		return "", fmt.Errorf("failed ioutil.TempFile: %w", err)
 	}
@@ -45,3 +53,29 @@ func Build(ctx context.Context, packageSpec, tempFilePattern string) (string, er
 
 	return path, nil
 }
+
+// BuildTest compiles the test binary for the package in the current directory at a temporary
+// location inside dir.
+//
+// The variables in env are added to the environment of `go test -c`, which lets you
+// cross-compile the test binary (e.g., GOOS=linux) for use somewhere else.
+func BuildTest(ctx context.Context, dir, tempFilePattern string, env []string) (string, error) {
+	file, err := ioutil.TempFile(dir, tempFilePattern)
+	if err != nil {
+		return "", fmt.Errorf("failed ioutil.TempFile: %w", err)
+	}
+
+	path := file.Name()
+	file.Close()
+
+	buildCmd := exec.CommandContext(ctx, "go", "test", "-c", "-o", path)
+	buildCmd.Env = append(os.Environ(), env...)
+	buildOutput, err := buildCmd.CombinedOutput()
+	if err != nil {
+		os.Remove(path)
+
+		return "", fmt.Errorf("go test -c failed: %w: %s", err, buildOutput)
+	}
+
+	return path, nil
+}
@@ -0,0 +1,388 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docket
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bloomberg/docket/internal/compose"
+)
+
+// junitLogLines is how many lines of each service's logs a failed run's JUnit report includes.
+const junitLogLines = 100
+
+// junitRun collects what goes into a run's JUnit XML report. A nil *junitRun collects nothing,
+// so that RunPrefix doesn't need to check whether DOCKET_JUNIT is set.
+type junitRun struct {
+	test    string
+	mode    string
+	compose *compose.Compose // nil until NewCompose succeeds
+
+	failedPhase string
+	failure     error
+	results     compose.TestResults
+	serviceLogs string
+	logsErr     error
+}
+
+// newJUnitRun returns a junitRun if DOCKET_JUNIT is set.
+func newJUnitRun(test, mode string) *junitRun {
+	if os.Getenv("DOCKET_JUNIT") == "" {
+		return nil
+	}
+
+	return makeJUnitRun(test, mode)
+}
+
+func makeJUnitRun(test, mode string) *junitRun {
+	return &junitRun{
+		test:        test,
+		mode:        mode,
+		compose:     nil,
+		failedPhase: "",
+		failure:     nil,
+		results:     compose.TestResults{},
+		serviceLogs: "",
+		logsErr:     nil,
+	}
+}
+
+// fail records that a phase failed. If err is a *phaseError, its phase is used instead of phase.
+// It also attaches the services' logs.
+func (r *junitRun) fail(ctx context.Context, phase string, err error) {
+	if r == nil || r.failure != nil {
+		return
+	}
+
+	var perr *phaseError
+	if errors.As(err, &perr) {
+		phase = perr.phase
+	}
+
+	r.failedPhase = phase
+	r.failure = err
+
+	r.attachLogs(ctx)
+}
+
+// attachLogsIfFailed attaches the services' logs if the test has failed. It has to run before
+// the app is taken down.
+func (r *junitRun) attachLogsIfFailed(ctx context.Context, t *testing.T) {
+	if r == nil || !t.Failed() {
+		return
+	}
+
+	r.attachLogs(ctx)
+}
+
+func (r *junitRun) attachLogs(ctx context.Context) {
+	if r.compose == nil || r.serviceLogs != "" || r.logsErr != nil {
+		return
+	}
+
+	logs, err := r.compose.Logs(ctx, junitLogLines)
+	r.serviceLogs = string(logs)
+	r.logsErr = err
+}
+
+// testResults returns where to collect the results of the tests inside the test service.
+func (r *junitRun) testResults() *compose.TestResults {
+	if r == nil {
+		return nil
+	}
+
+	return &r.results
+}
+
+// write writes the report into DOCKET_ARTIFACTS_DIR.
+func (r *junitRun) write(t *testing.T) {
+	if r == nil {
+		return
+	}
+
+	dir := os.Getenv("DOCKET_ARTIFACTS_DIR")
+	if dir == "" {
+		t.Errorf("DOCKET_JUNIT needs DOCKET_ARTIFACTS_DIR to say where to write the report")
+
+		return
+	}
+
+	project := ""
+	if r.compose != nil {
+		project = r.compose.ProjectName()
+	}
+
+	report := r.report(project, t.Failed(), t.Skipped())
+	if err := writeJUnitReport(dir, project, r.test, report); err != nil {
+		t.Errorf("failed to write JUnit report: %v", err)
+	}
+}
+
+// report makes the JUnit report. It has a "docket" suite with a test case for each phase of the
+// run, and a suite named after the Go test with the results of the tests that ran inside the
+// test service (or the Go test itself, if it ran on the host).
+func (r *junitRun) report(project string, failed, skipped bool) junitTestSuites {
+	var phases []compose.Phase
+	testOnHost := true
+	if r.compose != nil {
+		phases = r.compose.Timings().Phases()
+		testOnHost = r.compose.TestService() == ""
+	}
+
+	results := r.results.Results()
+
+	// A failed `go test` inside the test service is a test failure, not a docket failure,
+	// unless no test failed (for example, because the tests didn't compile).
+	failedPhase := r.failedPhase
+	if failedPhase == "exec" && hasFailedTest(results) {
+		failedPhase = ""
+	}
+
+	var testSuite junitTestSuite
+	if testOnHost {
+		testSuite = r.hostTestSuite(phases, failed && r.failedPhase != "down", skipped)
+	} else {
+		testSuite = newJUnitTestSuite(r.test, nil)
+		for _, res := range results {
+			testSuite.add(junitTestCaseFromResult(r.test, res))
+		}
+	}
+
+	return junitTestSuites{
+		XMLName: xml.Name{Space: "", Local: "testsuites"},
+		Suites:  []junitTestSuite{r.docketSuite(project, phases, failedPhase), testSuite},
+	}
+}
+
+func (r *junitRun) docketSuite(
+	project string, phases []compose.Phase, failedPhase string,
+) junitTestSuite {
+	suite := newJUnitTestSuite("docket", []junitProperty{
+		{Name: "test", Value: r.test},
+		{Name: "mode", Value: r.mode},
+		{Name: "project", Value: project},
+	})
+	suite.SystemOut = r.serviceLogs
+	if r.logsErr != nil {
+		suite.SystemErr = fmt.Sprintf("failed to get service logs: %v", r.logsErr)
+	}
+
+	failureRecorded := false
+	for _, p := range phases {
+		if p.Name == "test" {
+			continue // the test itself is in the other suite
+		}
+
+		tc := newJUnitTestCase("docket", p.Name, p.Duration)
+		if p.Name == failedPhase && !failureRecorded {
+			tc.Error = &junitMessage{Message: r.failure.Error(), Text: ""}
+			if failedPhase == "exec" {
+				tc.Error.Text = r.results.Output()
+			}
+			failureRecorded = true
+		}
+		suite.add(tc)
+	}
+
+	// Phases that failed before they could be timed, like "setup", still need a test case.
+	if failedPhase != "" && !failureRecorded {
+		tc := newJUnitTestCase("docket", failedPhase, 0)
+		tc.Error = &junitMessage{Message: r.failure.Error(), Text: ""}
+		suite.add(tc)
+	}
+
+	return suite
+}
+
+// hostTestSuite reports the Go test itself when it ran on the host. If a phase before the test
+// failed, the test didn't run.
+func (r *junitRun) hostTestSuite(phases []compose.Phase, failed, skipped bool) junitTestSuite {
+	suite := newJUnitTestSuite(r.test, nil)
+	switch r.failedPhase {
+	case "", "monitor", "down":
+	default:
+		return suite
+	}
+
+	tc := newJUnitTestCase(r.test, r.test, phaseDuration(phases, "test"))
+	switch {
+	case failed:
+		tc.Failure = &junitMessage{Message: "test failed", Text: ""}
+	case skipped:
+		tc.Skipped = &junitMessage{Message: "test skipped", Text: ""}
+	}
+	suite.add(tc)
+
+	return suite
+}
+
+func hasFailedTest(results []compose.TestResult) bool {
+	for _, res := range results {
+		if res.Status == "fail" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func phaseDuration(phases []compose.Phase, name string) time.Duration {
+	for _, p := range phases {
+		if p.Name == name {
+			return p.Duration
+		}
+	}
+
+	return 0
+}
+
+func junitTestCaseFromResult(className string, res compose.TestResult) junitTestCase {
+	tc := newJUnitTestCase(className, res.Name, res.Duration)
+
+	switch res.Status {
+	case "fail":
+		tc.Failure = &junitMessage{Message: "test failed", Text: res.Output}
+	case "skip":
+		tc.Skipped = &junitMessage{Message: "test skipped", Text: res.Output}
+	default:
+		tc.SystemOut = res.Output
+	}
+
+	return tc
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+func writeJUnitReport(dir, project, test string, report junitTestSuites) error {
+	if err := os.MkdirAll(dir, 0755); err != nil { //nolint:gosec // not secret
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JUnit report: %w", err)
+	}
+
+	name := fmt.Sprintf("docket-junit.%s.%s.xml", project, test)
+	if project == "" {
+		name = fmt.Sprintf("docket-junit.%s.xml", test) // NewCompose failed
+	}
+	path := filepath.Join(dir, nonFileNameChars.ReplaceAllString(name, "_"))
+
+	data = append([]byte(xml.Header), append(data, '\n')...)
+	if err := ioutil.WriteFile(path, data, 0644); err != nil { //nolint:gosec // not secret
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Errors     int              `xml:"errors,attr"`
+	Skipped    int              `xml:"skipped,attr"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+	TestCases  []junitTestCase  `xml:"testcase"`
+	SystemOut  string           `xml:"system-out,omitempty"`
+	SystemErr  string           `xml:"system-err,omitempty"`
+}
+
+func newJUnitTestSuite(name string, properties []junitProperty) junitTestSuite {
+	var props *junitProperties
+	if len(properties) > 0 {
+		props = &junitProperties{Properties: properties}
+	}
+
+	return junitTestSuite{
+		Name:       name,
+		Tests:      0,
+		Failures:   0,
+		Errors:     0,
+		Skipped:    0,
+		Properties: props,
+		TestCases:  nil,
+		SystemOut:  "",
+		SystemErr:  "",
+	}
+}
+
+func (s *junitTestSuite) add(tc junitTestCase) {
+	s.Tests++
+	switch {
+	case tc.Failure != nil:
+		s.Failures++
+	case tc.Error != nil:
+		s.Errors++
+	case tc.Skipped != nil:
+		s.Skipped++
+	}
+
+	s.TestCases = append(s.TestCases, tc)
+}
+
+type junitProperties struct {
+	Properties []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+func newJUnitTestCase(className, name string, d time.Duration) junitTestCase {
+	return junitTestCase{
+		ClassName: className,
+		Name:      name,
+		Time:      junitSeconds(d),
+		Failure:   nil,
+		Error:     nil,
+		Skipped:   nil,
+		SystemOut: "",
+	}
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
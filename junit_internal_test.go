@@ -0,0 +1,116 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docket
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bloomberg/docket/internal/compose"
+	"github.com/bloomberg/go-testgroup"
+)
+
+func Test_junit_internal(t *testing.T) {
+	testgroup.RunInParallel(t, &InternalJUnitTests{})
+}
+
+type InternalJUnitTests struct{}
+
+func (*InternalJUnitTests) Disabled(t *testgroup.T) {
+	var r *junitRun
+	r.fail(context.Background(), "up", errors.New("boom"))
+	t.Nil(r.testResults())
+}
+
+func (*InternalJUnitTests) SetupFailure(t *testgroup.T) {
+	r := makeJUnitRun("TestA", "full")
+	r.fail(context.Background(), "setup", errors.New("no docket files"))
+
+	report := r.report("", true, false)
+	t.Require.Len(report.Suites, 2)
+
+	docketSuite := report.Suites[0]
+	t.Equal(1, docketSuite.Errors)
+	t.Require.Len(docketSuite.TestCases, 1)
+	t.Equal("setup", docketSuite.TestCases[0].Name)
+	t.Equal("no docket files", docketSuite.TestCases[0].Error.Message)
+
+	// The test never ran.
+	t.Equal(0, report.Suites[1].Tests)
+}
+
+func (*InternalJUnitTests) PhaseError(t *testgroup.T) {
+	r := makeJUnitRun("TestA", "full")
+	r.fail(context.Background(), "up", &phaseError{phase: "pull", err: errors.New("no such image")})
+
+	t.Equal("pull", r.failedPhase)
+	t.Equal("no such image", r.failure.Error())
+}
+
+func (*InternalJUnitTests) TestOnHost(t *testgroup.T) {
+	r := makeJUnitRun("TestA", "full")
+
+	report := r.report("app", true, false)
+	t.Require.Len(report.Suites[1].TestCases, 1)
+	t.Equal(1, report.Suites[1].Failures)
+	t.Equal("TestA", report.Suites[1].TestCases[0].Name)
+
+	report = r.report("app", false, true)
+	t.Equal(1, report.Suites[1].Skipped)
+}
+
+func (*InternalJUnitTests) TestCaseFromResult(t *testgroup.T) {
+	tc := junitTestCaseFromResult("TestA", compose.TestResult{
+		Name: "TestA/sub", Status: "fail", Duration: 1500 * time.Millisecond, Output: "bad\n",
+	})
+
+	t.Equal("TestA", tc.ClassName)
+	t.Equal("TestA/sub", tc.Name)
+	t.Equal("1.500", tc.Time)
+	t.Equal(&junitMessage{Message: "test failed", Text: "bad\n"}, tc.Failure)
+}
+
+func (*InternalJUnitTests) WriteReport(t *testgroup.T) {
+	dir, err := ioutil.TempDir("", "docket-junit-test.")
+	t.Require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	r := makeJUnitRun("TestA/sub test", "full")
+	r.fail(context.Background(), "setup", errors.New("no <docket> files"))
+	t.Require.NoError(writeJUnitReport(dir, "app", r.test, r.report("app", true, false)))
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "docket-junit.app.TestA_sub_test.xml"))
+	t.Require.NoError(err)
+	t.Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="docket" tests="1" failures="0" errors="1" skipped="0">
+    <properties>
+      <property name="test" value="TestA/sub test"></property>
+      <property name="mode" value="full"></property>
+      <property name="project" value="app"></property>
+    </properties>
+    <testcase classname="docket" name="setup" time="0.000">
+      <error message="no &lt;docket&gt; files"></error>
+    </testcase>
+  </testsuite>
+  <testsuite name="TestA/sub test" tests="0" failures="0" errors="0" skipped="0"></testsuite>
+</testsuites>
+`, string(data))
+}
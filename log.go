@@ -0,0 +1,70 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docket
+
+import (
+	"io"
+
+	"github.com/bloomberg/docket/internal/logging"
+)
+
+// Logger receives docket's log messages, like the docker-compose commands it runs and how long
+// they took. Use SetLogger to replace the default Logger, which DOCKET_LOG and
+// DOCKET_LOG_LEVEL configure.
+type Logger = logging.Logger
+
+// LoggerFunc lets a function be a Logger.
+type LoggerFunc = logging.LoggerFunc
+
+// LogLevel is the importance of a log message.
+type LogLevel = logging.Level
+
+// Log levels, from least to most important.
+const (
+	LogDebug = logging.LevelDebug
+	LogInfo  = logging.LevelInfo
+	LogWarn  = logging.LevelWarn
+	LogError = logging.LevelError
+)
+
+// LogField is a structured attribute of a log message. Docket uses the keys "command",
+// "duration", "error", "file", "image", "mode", "output", "project", and
+// "service".
+type LogField = logging.Field
+
+// SetLogger makes docket log to l. SetLogger(nil) goes back to the default Logger.
+//
+// For example, to send docket's messages to a test's log:
+//
+//     docket.SetLogger(docket.LoggerFunc(
+//         func(level docket.LogLevel, msg string, fields ...docket.LogField) {
+//             t.Log(level, msg, fields)
+//         }))
+//     defer docket.SetLogger(nil)
+func SetLogger(l Logger) {
+	logging.Set(l)
+}
+
+// NewTextLogger returns a Logger that writes messages at level or above to w as lines of text.
+// It's the default Logger (writing to os.Stderr at LogInfo).
+func NewTextLogger(w io.Writer, level LogLevel) Logger {
+	return logging.NewText(w, level)
+}
+
+// NewJSONLogger returns a Logger that writes messages at level or above to w as JSON objects,
+// one per line. Durations are in seconds.
+func NewJSONLogger(w io.Writer, level LogLevel) Logger {
+	return logging.NewJSON(w, level)
+}
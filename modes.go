@@ -0,0 +1,46 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docket
+
+import (
+	"github.com/bloomberg/docket/internal/compose"
+)
+
+// Mode describes a docket mode that is available in the current directory.
+type Mode struct {
+	// Name is the value to use for DOCKET_MODE.
+	Name string
+
+	// Files are the docket files for the mode, in the order docket passes them to docker-compose.
+	Files []string
+}
+
+// Modes returns the modes that have docket files starting with prefix in the current directory.
+//
+// A mode is available if there is at least one file named 'prefix.MODE.yaml' or
+// 'prefix.MODE.*.yaml' (.yml files are also allowed).
+func Modes(prefix string) ([]Mode, error) {
+	found, err := compose.FindModes(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	modes := make([]Mode, len(found))
+	for i, m := range found {
+		modes[i] = Mode{Name: m.Name, Files: m.Files}
+	}
+
+	return modes, nil
+}
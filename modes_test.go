@@ -0,0 +1,45 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docket_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bloomberg/docket"
+	"github.com/bloomberg/go-testgroup"
+)
+
+func Test_modes(t *testing.T) {
+	testgroup.RunSerially(t, &ModesTests{}) // cannot parallelize due to chdir
+}
+
+type ModesTests struct{}
+
+func (*ModesTests) RedisPinger(t *testgroup.T) {
+	t.Require.NoError(os.Chdir(filepath.Join("testdata", "03_redispinger-service")))
+	defer func() {
+		t.NoError(os.Chdir(filepath.Join("..", "..")))
+	}()
+
+	modes, err := docket.Modes("docket")
+
+	t.NoError(err)
+	t.Equal([]docket.Mode{
+		{Name: "debug", Files: []string{"docket.yaml", "docket.debug.yaml"}},
+		{Name: "full", Files: []string{"docket.yaml", "docket.full.yaml"}},
+	}, modes)
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docket
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bloomberg/docket/internal/compose"
+)
+
+const defaultMonitorInterval = 2 * time.Second
+
+// monitorInterval returns how often to check the app's containers while the test runs, or 0 if
+// DOCKET_MONITOR isn't set or DOCKET_MONITOR_INTERVAL turns off monitoring.
+func monitorInterval() (time.Duration, error) {
+	if os.Getenv("DOCKET_MONITOR") == "" {
+		return 0, nil
+	}
+
+	return durationFromEnv("DOCKET_MONITOR_INTERVAL", defaultMonitorInterval)
+}
+
+// durationFromEnv parses the duration in an environment variable, which can't be negative.
+func durationFromEnv(name string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s must be a duration like 2s or 0, not %q", name, value)
+	}
+
+	return d, nil
+}
+
+// docketTest runs the test while watching the app's containers. If a service exits or restarts
+// unexpectedly, the test fails, and if it runs inside the test service, it stops.
+func docketTest(
+	ctx context.Context, t *testing.T, cmp *compose.Compose, junit *junitRun, testFunc func(),
+) {
+	t.Helper()
+
+	interval, err := monitorInterval()
+	if err != nil {
+		junit.fail(ctx, "monitor", err)
+		t.Fatal(err)
+	}
+
+	testCtx := ctx
+	var monitor *compose.Monitor
+
+	if interval > 0 {
+		monitor, err = cmp.StartMonitor(ctx, interval, func(exited *compose.ServiceExitedError) {
+			t.Error(exited)
+		})
+		if err != nil {
+			junit.fail(ctx, "monitor", err)
+			t.Fatalf("failed to monitor the app: %v", err)
+		}
+		defer monitor.Stop()
+
+		testCtx = monitor.Context()
+	}
+
+	err = cmp.RunTestfuncOrExecGoTest(testCtx, t.Name(), testFunc, junit.testResults())
+
+	if monitor != nil {
+		if exited := monitor.Stop(); exited != nil {
+			junit.fail(ctx, "monitor", exited)
+
+			return // the monitor already failed the test
+		}
+	}
+
+	if err != nil {
+		junit.fail(ctx, "exec", err)
+		t.Fatalf("compose.RunTestfuncOrExecGoTest failed: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docket
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bloomberg/go-testgroup"
+)
+
+func Test_monitor_internal(t *testing.T) {
+	testgroup.RunSerially(t, &InternalMonitorTests{}) // cannot parallelize due to os.Setenv
+}
+
+type InternalMonitorTests struct{}
+
+func (*InternalMonitorTests) Interval(t *testgroup.T) {
+	defer os.Unsetenv("DOCKET_MONITOR")
+	defer os.Unsetenv("DOCKET_MONITOR_INTERVAL")
+
+	t.NoError(os.Setenv("DOCKET_MONITOR_INTERVAL", "500ms"))
+
+	interval, err := monitorInterval()
+	t.NoError(err)
+	t.Equal(time.Duration(0), interval, "without DOCKET_MONITOR")
+
+	t.NoError(os.Setenv("DOCKET_MONITOR", "1"))
+
+	for value, expected := range map[string]time.Duration{
+		"":      defaultMonitorInterval,
+		"500ms": 500 * time.Millisecond,
+		"0":     0,
+	} {
+		t.NoError(os.Setenv("DOCKET_MONITOR_INTERVAL", value))
+
+		interval, err := monitorInterval()
+		t.NoError(err, value)
+		t.Equal(expected, interval, value)
+	}
+
+	for _, value := range []string{"soon", "-1s"} {
+		t.NoError(os.Setenv("DOCKET_MONITOR_INTERVAL", value))
+
+		_, err := monitorInterval()
+		t.Error(err, value)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/bloomberg/docket/internal/compose"
+)
+
+var errDuplicatePortEnv = errors.New("more than one published port maps to the same variable")
+
+const defaultPortEnvFormat = `DOCKET_{{.Service}}_{{.Port}}{{if ne .Protocol "TCP"}}_{{.Protocol}}{{end}}_ADDR`
+
+// docketPortEnv exports an environment variable holding the host address of each published port
+// and returns a function that restores the environment.
+func docketPortEnv(ctx context.Context, compose *compose.Compose) (restore func(), err error) {
+	restore = func() {}
+
+	if os.Getenv("DOCKET_PORT_ENV") == "" {
+		return restore, nil
+	}
+
+	format := os.Getenv("DOCKET_PORT_ENV_FORMAT")
+	if format == "" {
+		format = defaultPortEnvFormat
+	}
+
+	tmpl, err := template.New("DOCKET_PORT_ENV_FORMAT").Parse(format)
+	if err != nil {
+		return restore, fmt.Errorf("failed to parse DOCKET_PORT_ENV_FORMAT: %w", err)
+	}
+
+	ports, err := compose.PublishedPorts(ctx)
+	if err != nil {
+		return restore, fmt.Errorf("failed compose.PublishedPorts: %w", err)
+	}
+
+	env, err := makePortEnv(tmpl, ports)
+	if err != nil {
+		return restore, fmt.Errorf("failed to make published port environment variables: %w", err)
+	}
+
+	restore, err = setEnv(env)
+	if err != nil {
+		restore()
+
+		return func() {}, fmt.Errorf("failed to set published port environment variables: %w", err)
+	}
+
+	return restore, nil
+}
+
+type portEnvData struct {
+	Service  string // uppercased, with characters that aren't allowed in names replaced by _
+	Port     int
+	Protocol string // uppercased
+}
+
+var nonEnvNameChars = regexp.MustCompile(`[^A-Z0-9_]`)
+
+func makePortEnv(tmpl *template.Template, ports []compose.PublishedPort) (map[string]string, error) {
+	env := make(map[string]string, len(ports))
+
+	for _, p := range ports {
+		data := portEnvData{
+			Service:  nonEnvNameChars.ReplaceAllString(strings.ToUpper(p.Service), "_"),
+			Port:     p.Port,
+			Protocol: strings.ToUpper(p.Protocol),
+		}
+
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, data); err != nil {
+			return nil, fmt.Errorf("failed to execute template: %w", err)
+		}
+
+		name := sb.String()
+		if _, exists := env[name]; exists {
+			return nil, fmt.Errorf("%w %q", errDuplicatePortEnv, name)
+		}
+
+		env[name] = p.HostAddr
+	}
+
+	return env, nil
+}
+
+// setEnv sets environment variables and returns a function to restore their previous values.
+func setEnv(env map[string]string) (restore func(), err error) {
+	type previous struct {
+		value string
+		set   bool
+	}
+
+	saved := make(map[string]previous, len(env))
+
+	restore = func() {
+		for name, prev := range saved {
+			if prev.set {
+				os.Setenv(name, prev.value)
+			} else {
+				os.Unsetenv(name)
+			}
+		}
+	}
+
+	for name, value := range env {
+		prevValue, prevSet := os.LookupEnv(name)
+		saved[name] = previous{value: prevValue, set: prevSet}
+
+		if err := os.Setenv(name, value); err != nil {
+			return restore, fmt.Errorf("failed to set %s: %w", name, err)
+		}
+	}
+
+	return restore, nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docket
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"text/template"
+
+	"github.com/bloomberg/docket/internal/compose"
+	"github.com/bloomberg/go-testgroup"
+)
+
+func Test_portenv_internal(t *testing.T) {
+	testgroup.RunSerially(t, &InternalPortEnvTests{}) // cannot parallelize due to os.Setenv
+}
+
+type InternalPortEnvTests struct{}
+
+func (*InternalPortEnvTests) DefaultFormat(t *testgroup.T) {
+	ports := []compose.PublishedPort{
+		{Service: "redis", Port: 6379, Protocol: "tcp", HostAddr: "127.0.0.1:49153"},
+		{Service: "my-dns.1", Port: 53, Protocol: "udp", HostAddr: "127.0.0.1:49154"},
+	}
+
+	env, err := makePortEnv(template.Must(template.New("").Parse(defaultPortEnvFormat)), ports)
+
+	t.NoError(err)
+	t.Equal(map[string]string{
+		"DOCKET_REDIS_6379_ADDR":      "127.0.0.1:49153",
+		"DOCKET_MY_DNS_1_53_UDP_ADDR": "127.0.0.1:49154",
+	}, env)
+}
+
+func (*InternalPortEnvTests) CustomFormat(t *testgroup.T) {
+	ports := []compose.PublishedPort{
+		{Service: "redis", Port: 6379, Protocol: "tcp", HostAddr: "127.0.0.1:49153"},
+	}
+
+	env, err := makePortEnv(template.Must(template.New("").Parse("{{.Service}}_ADDR")), ports)
+	t.NoError(err)
+	t.Equal(map[string]string{"REDIS_ADDR": "127.0.0.1:49153"}, env)
+
+	ports = append(ports, compose.PublishedPort{
+		Service: "redis", Port: 6380, Protocol: "tcp", HostAddr: "127.0.0.1:49154",
+	})
+	_, err = makePortEnv(template.Must(template.New("").Parse("{{.Service}}_ADDR")), ports)
+	t.True(errors.Is(err, errDuplicatePortEnv), err)
+}
+
+func (*InternalPortEnvTests) SetAndRestore(t *testgroup.T) {
+	const existing, missing = "DOCKET_TEST_PORT_ENV_EXISTING", "DOCKET_TEST_PORT_ENV_MISSING"
+
+	t.Require.NoError(os.Setenv(existing, "before"))
+	defer os.Unsetenv(existing)
+
+	restore, err := setEnv(map[string]string{existing: "after", missing: "new"})
+	t.Require.NoError(err)
+
+	t.Equal("after", os.Getenv(existing))
+	t.Equal("new", os.Getenv(missing))
+
+	restore()
+
+	t.Equal("before", os.Getenv(existing))
+	_, isSet := os.LookupEnv(missing)
+	t.False(isSet)
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bloomberg/docket/internal/compose"
+	"github.com/bloomberg/docket/internal/logging"
+)
+
+// Pull policies for DOCKET_PULL_POLICY.
+const (
+	pullPolicyDefault = ""        // don't pull; `docker-compose up` pulls missing images
+	pullPolicyMissing = "missing" // pull the images that aren't present locally
+	pullPolicyAlways  = "always"  // pull every image
+	pullPolicyNever   = "never"   // fail if an image isn't present locally
+)
+
+const (
+	defaultPullRetries = 2
+
+	// pullBackoff is how long to wait before retrying a failed pull. It doubles after each
+	// retry.
+	pullBackoff = time.Second
+)
+
+var (
+	errBadPullPolicy = errors.New("bad DOCKET_PULL_POLICY")
+	errMissingImages = errors.New("images aren't present locally and DOCKET_PULL_POLICY=never")
+)
+
+// pullPolicy returns DOCKET_PULL_POLICY. For compatibility, DOCKET_PULL means "always".
+func pullPolicy() (string, error) {
+	policy := os.Getenv("DOCKET_PULL_POLICY")
+
+	switch policy {
+	case pullPolicyDefault:
+		if os.Getenv("DOCKET_PULL") != "" {
+			return pullPolicyAlways, nil
+		}
+
+		return pullPolicyDefault, nil
+	case pullPolicyMissing, pullPolicyAlways, pullPolicyNever:
+		return policy, nil
+	}
+
+	return "", fmt.Errorf("%w %q (use missing, always, or never)", errBadPullPolicy, policy)
+}
+
+func pullRetries() (int, error) {
+	value := os.Getenv("DOCKET_PULL_RETRIES")
+	if value == "" {
+		return defaultPullRetries, nil
+	}
+
+	retries, err := strconv.Atoi(value)
+	if err != nil || retries < 0 {
+		return 0, fmt.Errorf("DOCKET_PULL_RETRIES must be a number like 2 or 0, not %q", value)
+	}
+
+	return retries, nil
+}
+
+// docketPull pulls images according to DOCKET_PULL_POLICY.
+func docketPull(ctx context.Context, compose *compose.Compose) error {
+	policy, err := pullPolicy()
+	if err != nil {
+		return err
+	}
+
+	retries, err := pullRetries()
+	if err != nil {
+		return err
+	}
+
+	pullArgs := strings.Fields(os.Getenv("DOCKET_PULL_OPTS"))
+
+	switch policy {
+	case pullPolicyDefault:
+		return nil
+
+	case pullPolicyNever, pullPolicyMissing:
+		missing, err := compose.MissingImages(ctx)
+		if err != nil {
+			return err
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+
+		if policy == pullPolicyNever {
+			return fmt.Errorf("%w: %s", errMissingImages, joinServiceImages(missing))
+		}
+
+		for _, si := range missing {
+			pullArgs = append(pullArgs, si.Service)
+		}
+	}
+
+	err = retryPull(ctx, retries, pullBackoff, func() error {
+		return compose.Pull(ctx, pullArgs)
+	})
+	if err != nil {
+		return fmt.Errorf("failed compose.Pull: %w", err)
+	}
+
+	return nil
+}
+
+func joinServiceImages(images []compose.ServiceImage) string {
+	s := make([]string, len(images))
+	for i, si := range images {
+		s[i] = si.String()
+	}
+
+	return strings.Join(s, ", ")
+}
+
+// retryPull calls pull until it succeeds or it has retried retries times, waiting backoff
+// before the first retry and twice as long before each one after that.
+func retryPull(ctx context.Context, retries int, backoff time.Duration, pull func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := pull()
+		if err == nil || attempt >= retries {
+			return err
+		}
+
+		logging.Get().Log(logging.LevelWarn, "pull failed; retrying", logging.Error(err),
+			logging.Field{Key: "retry_in", Value: backoff})
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docket
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bloomberg/go-testgroup"
+)
+
+func Test_pull_internal(t *testing.T) {
+	testgroup.RunSerially(t, &InternalPullTests{}) // cannot parallelize due to os.Setenv
+}
+
+type InternalPullTests struct{}
+
+func (*InternalPullTests) Policy(t *testgroup.T) {
+	defer os.Unsetenv("DOCKET_PULL")
+	defer os.Unsetenv("DOCKET_PULL_POLICY")
+
+	for _, tc := range []struct {
+		pull, policy, expected string
+	}{
+		{"", "", pullPolicyDefault},
+		{"1", "", pullPolicyAlways},
+		{"", "missing", pullPolicyMissing},
+		{"1", "never", pullPolicyNever},
+		{"", "always", pullPolicyAlways},
+	} {
+		t.NoError(os.Setenv("DOCKET_PULL", tc.pull))
+		t.NoError(os.Setenv("DOCKET_PULL_POLICY", tc.policy))
+
+		policy, err := pullPolicy()
+		t.NoError(err)
+		t.Equal(tc.expected, policy, "%+v", tc)
+	}
+
+	t.NoError(os.Setenv("DOCKET_PULL_POLICY", "sometimes"))
+	_, err := pullPolicy()
+	t.True(errors.Is(err, errBadPullPolicy), err)
+}
+
+func (*InternalPullTests) Retries(t *testgroup.T) {
+	defer os.Unsetenv("DOCKET_PULL_RETRIES")
+
+	retries, err := pullRetries()
+	t.NoError(err)
+	t.Equal(defaultPullRetries, retries)
+
+	t.NoError(os.Setenv("DOCKET_PULL_RETRIES", "5"))
+	retries, err = pullRetries()
+	t.NoError(err)
+	t.Equal(5, retries)
+
+	t.NoError(os.Setenv("DOCKET_PULL_RETRIES", "-1"))
+	_, err = pullRetries()
+	t.Error(err)
+}
+
+func (*InternalPullTests) RetryPull(t *testgroup.T) {
+	errPull := errors.New("TLS handshake timeout")
+
+	calls := 0
+	err := retryPull(context.Background(), 2, time.Millisecond, func() error {
+		calls++
+		if calls < 3 {
+			return errPull
+		}
+
+		return nil
+	})
+	t.NoError(err)
+	t.Equal(3, calls)
+
+	calls = 0
+	err = retryPull(context.Background(), 1, time.Millisecond, func() error {
+		calls++
+
+		return errPull
+	})
+	t.Equal(errPull, err)
+	t.Equal(2, calls)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls = 0
+	err = retryPull(ctx, 5, time.Hour, func() error {
+		calls++
+
+		return errPull
+	})
+	t.Equal(errPull, err)
+	t.Equal(1, calls)
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docket
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bloomberg/docket/internal/compose"
+)
+
+// PhaseTiming is how long one phase of a docket run took, like "config", "pull", "up", "exec",
+// or "down".
+type PhaseTiming struct {
+	Name     string
+	Duration time.Duration
+}
+
+func makePhaseTimings(phases []compose.Phase) []PhaseTiming {
+	if phases == nil {
+		return nil
+	}
+
+	timings := make([]PhaseTiming, len(phases))
+	for i, p := range phases {
+		timings[i] = PhaseTiming{Name: p.Name, Duration: p.Duration}
+	}
+
+	return timings
+}
+
+// timingsReport is the JSON file that docket writes into DOCKET_ARTIFACTS_DIR.
+type timingsReport struct {
+	Test         string              `json:"test"`
+	Mode         string              `json:"mode"`
+	Project      string              `json:"project"`
+	Phases       []timingsReportItem `json:"phases"`
+	TotalSeconds float64             `json:"total_seconds"`
+}
+
+type timingsReportItem struct {
+	Name    string  `json:"name"`
+	Seconds float64 `json:"seconds"`
+}
+
+func makeTimingsReport(
+	test, mode, project string, phases []PhaseTiming, total time.Duration,
+) timingsReport {
+	items := make([]timingsReportItem, len(phases))
+	for i, p := range phases {
+		items[i] = timingsReportItem{Name: p.Name, Seconds: p.Duration.Seconds()}
+	}
+
+	return timingsReport{
+		Test:         test,
+		Mode:         mode,
+		Project:      project,
+		Phases:       items,
+		TotalSeconds: total.Seconds(),
+	}
+}
+
+// docketTimings writes the run's timings into DOCKET_ARTIFACTS_DIR (if it's set) and prints
+// them as a table (if DOCKET_TIMINGS is set).
+func docketTimings(t *testing.T, compose *compose.Compose, start time.Time) {
+	phases := makePhaseTimings(compose.Timings().Phases())
+	total := time.Since(start)
+
+	if dir := os.Getenv("DOCKET_ARTIFACTS_DIR"); dir != "" {
+		report := makeTimingsReport(t.Name(), compose.Mode(), compose.ProjectName(), phases, total)
+		if err := writeTimingsReport(dir, report); err != nil {
+			t.Errorf("failed to write timings: %v", err)
+		}
+	}
+
+	if os.Getenv("DOCKET_TIMINGS") != "" {
+		printTimings(os.Stderr, t.Name(), phases, total)
+	}
+}
+
+var nonFileNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// timingsFileName returns the name of a report's file. It includes the project so that tests
+// with the same name in different packages don't overwrite each other's reports.
+func timingsFileName(report timingsReport) string {
+	return nonFileNameChars.ReplaceAllString(
+		fmt.Sprintf("docket-timings.%s.%s.json", report.Project, report.Test), "_")
+}
+
+func writeTimingsReport(dir string, report timingsReport) error {
+	if err := os.MkdirAll(dir, 0755); err != nil { //nolint:gosec // not secret
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal timings: %w", err)
+	}
+
+	path := filepath.Join(dir, timingsFileName(report))
+	data = append(data, '\n')
+	if err := ioutil.WriteFile(path, data, 0644); err != nil { //nolint:gosec // not secret
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// printTimings prints a table of how long each phase took.
+func printTimings(w io.Writer, test string, phases []PhaseTiming, total time.Duration) {
+	fmt.Fprintf(w, "docket timings for %s:\n", test)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, p := range phases {
+		fmt.Fprintf(tw, "  %s\t%8.2fs\n", p.Name, p.Duration.Seconds())
+	}
+	fmt.Fprintf(tw, "  total\t%8.2fs\n", total.Seconds())
+	tw.Flush()
+}
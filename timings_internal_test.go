@@ -0,0 +1,79 @@
+// Copyright 2026 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docket
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bloomberg/go-testgroup"
+)
+
+func Test_timings_internal(t *testing.T) {
+	testgroup.RunInParallel(t, &InternalTimingsTests{})
+}
+
+type InternalTimingsTests struct{}
+
+var testPhases = []PhaseTiming{
+	{Name: "config", Duration: 500 * time.Millisecond},
+	{Name: "up", Duration: 2 * time.Second},
+}
+
+func (*InternalTimingsTests) NoMode(t *testgroup.T) {
+	t.Nil(Context{}.Timings())
+}
+
+func (*InternalTimingsTests) WriteReport(t *testgroup.T) {
+	dir, err := ioutil.TempDir("", "docket-timings-test.")
+	t.Require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	report := makeTimingsReport("TestA/sub test", "full", "docket-app", testPhases, 3*time.Second)
+	t.Require.NoError(writeTimingsReport(filepath.Join(dir, "artifacts"), report))
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "artifacts",
+		"docket-timings.docket-app.TestA_sub_test.json"))
+	t.Require.NoError(err)
+
+	var got map[string]interface{}
+	t.Require.NoError(json.Unmarshal(data, &got))
+	t.Equal(map[string]interface{}{
+		"test":    "TestA/sub test",
+		"mode":    "full",
+		"project": "docket-app",
+		"phases": []interface{}{
+			map[string]interface{}{"name": "config", "seconds": 0.5},
+			map[string]interface{}{"name": "up", "seconds": 2.0},
+		},
+		"total_seconds": 3.0,
+	}, got)
+}
+
+func (*InternalTimingsTests) PrintTable(t *testgroup.T) {
+	var buf bytes.Buffer
+	printTimings(&buf, "TestA", testPhases, 3*time.Second)
+
+	t.Equal(`docket timings for TestA:
+  config      0.50s
+  up          2.00s
+  total       3.00s
+`, buf.String())
+}